@@ -1,25 +1,207 @@
 package log
 
-import "context"
+import (
+	"context"
+	"slices"
+)
 
 // argsKey
 // Структура-ключ для хранения набора аргументов в контексте
 type argsKey struct{}
 
-// GetContextArgs
-// Возвращает набор аргументов, содержащийся в context.Context по ключу argsKey{}. Если context.Context не указан или по ключу ничего не
-// найдено, возвращает пустой набор (nil)
-func GetContextArgs(ctx context.Context) []Arg {
+// keyedArg
+// Аргумент, сохраненный в контексте по ключу с помощью SetContextArg
+type keyedArg struct {
+	key any
+	arg Arg
+}
+
+// contextArgs
+// Неизменяемый набор аргументов, хранящийся в context.Context по ключу argsKey{}. Каждое изменение создает копию
+type contextArgs struct {
+	// args
+	// Аргументы, установленные с помощью AddContextArgs и SetContextArgs
+	args []Arg
+
+	// keyed
+	// Аргументы, установленные с помощью SetContextArg, в порядке первой установки ключа
+	keyed []keyedArg
+
+	// all
+	// Итоговый набор: args, а затем аргументы из keyed
+	all []Arg
+
+	// parent
+	// Набор, из которого получен данный с помощью AddContextArgs, SetContextArg или DeleteContextArg: args начинается
+	// с parent.args
+	parent *contextArgs
+}
+
+// derives
+// Сообщает, получен ли набор из bound или совпадает с ним
+func (ca *contextArgs) derives(bound *contextArgs) bool {
+	if bound == nil {
+		return false
+	}
+
+	for p := ca; p != nil; p = p.parent {
+		if p == bound {
+			return true
+		}
+	}
+
+	return false
+}
+
+// unboundArgs
+// Возвращает аргументы, которые добавляются в Logger при замене набора bound на данный с помощью
+// Logger.WithContext: аргументы, установленные с помощью AddContextArgs и SetContextArgs и еще не добавленные вместе с
+// bound. Аргументы, установленные по ключу, в Logger не добавляются и записываются при каждом логе, кроме аргументов
+// bound по ключам, отсутствующим в наборе, не полученном из bound
+func (ca *contextArgs) unboundArgs(bound *contextArgs) []Arg {
+	if ca == nil {
+		return nil
+	}
+
+	if ca.derives(bound) {
+		return ca.args[len(bound.args):]
+	}
+
+	if bound == nil {
+		return ca.args
+	}
+
+	args := slices.Clip(ca.args)
+	for _, ka := range bound.keyed {
+		if !ca.hasKey(ka.key) {
+			args = append(args, ka.arg)
+		}
+	}
+
+	return args
+}
+
+// keyedArgs
+// Возвращает аргументы, установленные по ключу с помощью SetContextArg
+func (ca *contextArgs) keyedArgs() []Arg {
+	if ca == nil {
+		return nil
+	}
+
+	return ca.all[len(ca.args):]
+}
+
+// appendUnbound
+// Добавляет в dst аргументы набора, которые не были добавлены в Logger вместе с набором bound, и аргументы,
+// установленные по ключу. Если набор получен из bound, аргументы по ключу берутся только из набора, поэтому
+// замененные и удаленные после Logger.WithContext аргументы не записываются. Иначе к ним добавляются аргументы bound
+// по ключам, отсутствующим в наборе. Если набор не указан, добавляются аргументы bound по ключу
+func (ca *contextArgs) appendUnbound(dst []Arg, bound *contextArgs) []Arg {
+	if ca == nil {
+		return append(dst, bound.keyedArgs()...)
+	}
+
+	if ca.derives(bound) {
+		dst = append(dst, ca.args[len(bound.args):]...)
+		return append(dst, ca.keyedArgs()...)
+	}
+
+	dst = append(dst, ca.all...)
+
+	if bound != nil {
+		for _, ka := range bound.keyed {
+			if !ca.hasKey(ka.key) {
+				dst = append(dst, ka.arg)
+			}
+		}
+	}
+
+	return dst
+}
+
+// hasKey
+// Сообщает, установлен ли в наборе аргумент по ключу key
+func (ca *contextArgs) hasKey(key any) bool {
+	for _, ka := range ca.keyed {
+		if ka.key == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+func newContextArgs(args []Arg, keyed []keyedArg) *contextArgs {
+	all := args
+	if len(keyed) > 0 {
+		all = make([]Arg, 0, len(args)+len(keyed))
+		all = append(all, args...)
+
+		for _, ka := range keyed {
+			all = append(all, ka.arg)
+		}
+	}
+
+	return &contextArgs{
+		args:  args,
+		keyed: keyed,
+		all:   all,
+	}
+}
+
+// getContextArgs
+// Возвращает набор аргументов из context.Context или nil
+func getContextArgs(ctx context.Context) *contextArgs {
 	if ctx == nil {
 		return nil
 	}
 
-	args, ok := ctx.Value(argsKey{}).([]Arg)
+	ca, ok := ctx.Value(argsKey{}).(*contextArgs)
 	if !ok {
 		return nil
 	}
 
-	return args
+	return ca
+}
+
+// GetContextArgs
+// Возвращает набор аргументов, содержащийся в context.Context: аргументы, установленные с помощью AddContextArgs и
+// SetContextArgs, а затем аргументы, установленные по ключу с помощью SetContextArg. Если context.Context не указан или
+// аргументы отсутствуют, возвращает пустой набор (nil)
+func GetContextArgs(ctx context.Context) []Arg {
+	ca := getContextArgs(ctx)
+	if ca == nil {
+		return nil
+	}
+
+	return ca.all
+}
+
+// BoundContext
+// Набор аргументов context.Context, добавленный в Logger с помощью Logger.WithContext. Позволяет собственным
+// реализациям Logger записывать аргументы context.Context так же, как драйверы: без повторной записи добавленных
+// аргументов, с актуальными значениями аргументов, установленных по ключу. Нулевое значение - пустой набор
+type BoundContext struct {
+	ca *contextArgs
+}
+
+// Bind
+// Возвращает набор аргументов context.Context для Logger, созданного с помощью Logger.WithContext, и аргументы,
+// которые необходимо добавить в Logger. Если context.Context не содержит аргументов или содержит тот же набор,
+// возвращает false
+func (b BoundContext) Bind(ctx context.Context) (BoundContext, []Arg, bool) {
+	ca := getContextArgs(ctx)
+	if ca == nil || ca == b.ca {
+		return b, nil, false
+	}
+
+	return BoundContext{ca: ca}, ca.unboundArgs(b.ca), true
+}
+
+// AppendArgs
+// Добавляет в dst аргументы context.Context, которые необходимо записать в лог Logger с этим набором
+func (b BoundContext) AppendArgs(dst []Arg, ctx context.Context) []Arg {
+	return getContextArgs(ctx).appendUnbound(dst, b.ca)
 }
 
 // AddContextArgs
@@ -30,48 +212,115 @@ func AddContextArgs(ctx context.Context, args ...Arg) context.Context {
 		return ctx
 	}
 
-	existingArgs := GetContextArgs(ctx)
-	if len(existingArgs) > 0 {
-		newArgs := make([]Arg, 0, len(existingArgs)+len(args))
-		newArgs = append(newArgs, existingArgs...)
-		newArgs = append(newArgs, args...)
-
-		args = newArgs
+	ca := getContextArgs(ctx)
+	if ca == nil {
+		return SetContextArgs(ctx, args...)
 	}
 
-	return SetContextArgs(ctx, args...)
+	newArgs := make([]Arg, 0, len(ca.args)+len(args))
+	newArgs = append(newArgs, ca.args...)
+	newArgs = append(newArgs, args...)
+
+	next := newContextArgs(newArgs, ca.keyed)
+	next.parent = ca
+
+	return context.WithValue(ctx, argsKey{}, next)
 }
 
 // SetContextArgs
-// Устанавливает набор аргументов в context.Context по ключу argsKey{}, перезаписывая имеющийся набор, если таковой был. Если
-// context.Context не указан или список аргументов пуст, возвращает исходный context.Context без изменений
+// Устанавливает набор аргументов в context.Context по ключу argsKey{}, перезаписывая имеющийся набор, если таковой был. Аргументы,
+// установленные по ключу с помощью SetContextArg, сохраняются. Если context.Context не указан или список аргументов пуст, возвращает
+// исходный context.Context без изменений
 func SetContextArgs(ctx context.Context, args ...Arg) context.Context {
 	if ctx == nil || len(args) == 0 {
 		return ctx
 	}
 
-	return context.WithValue(ctx, argsKey{}, args)
+	var keyed []keyedArg
+	if ca := getContextArgs(ctx); ca != nil {
+		keyed = ca.keyed
+	}
+
+	return context.WithValue(ctx, argsKey{}, newContextArgs(args, keyed))
 }
 
 // GetContextArg
-// Возвращает один аргумент и статус получения, содержащийся в context.Context по указанному ключу
+// Возвращает один аргумент и статус получения, установленный в context.Context по указанному ключу с помощью SetContextArg
 func GetContextArg(ctx context.Context, key any) (a Arg, ok bool) {
-	if key == nil {
+	ca := getContextArgs(ctx)
+	if ca == nil || key == nil {
 		return a, false
 	}
 
-	a, ok = ctx.Value(argsKey{}).(Arg)
-	return a, ok
+	for _, ka := range ca.keyed {
+		if ka.key == key {
+			return ka.arg, true
+		}
+	}
+
+	return a, false
 }
 
 // SetContextArg
-// Возвращает context.Context, содержащий указанный аргумент по указанному ключу. Если context.Context или ключ не указан, возвращает
-// исходный context.Context без изменений
+// Возвращает context.Context, содержащий указанный аргумент по указанному ключу. Аргумент записывается во все логи с этим
+// context.Context после аргументов, установленных с помощью AddContextArgs и SetContextArgs. Повторная установка по тому же ключу
+// заменяет аргумент, сохраняя его позицию. Если context.Context или ключ не указан, возвращает исходный context.Context без изменений.
+// Ключ должен быть сравнимым, рекомендуется использовать собственный тип, как для context.WithValue
 func SetContextArg(ctx context.Context, key any, arg Arg) context.Context {
 	if ctx == nil || key == nil {
 		return ctx
 	}
-	return context.WithValue(ctx, key, arg)
+
+	var args []Arg
+	var keyed []keyedArg
+
+	if ca := getContextArgs(ctx); ca != nil {
+		args = ca.args
+		keyed = make([]keyedArg, 0, len(ca.keyed)+1)
+		keyed = append(keyed, ca.keyed...)
+	}
+
+	replaced := false
+	for i := range keyed {
+		if keyed[i].key == key {
+			keyed[i].arg = arg
+			replaced = true
+
+			break
+		}
+	}
+
+	if !replaced {
+		keyed = append(keyed, keyedArg{key: key, arg: arg})
+	}
+
+	next := newContextArgs(args, keyed)
+	next.parent = getContextArgs(ctx)
+
+	return context.WithValue(ctx, argsKey{}, next)
+}
+
+// DeleteContextArg
+// Возвращает context.Context без аргумента, установленного по указанному ключу с помощью SetContextArg. Если аргумент по ключу
+// отсутствует, возвращает исходный context.Context без изменений
+func DeleteContextArg(ctx context.Context, key any) context.Context {
+	if _, ok := GetContextArg(ctx, key); !ok {
+		return ctx
+	}
+
+	ca := getContextArgs(ctx)
+
+	keyed := make([]keyedArg, 0, len(ca.keyed)-1)
+	for _, ka := range ca.keyed {
+		if ka.key != key {
+			keyed = append(keyed, ka)
+		}
+	}
+
+	next := newContextArgs(ca.args, keyed)
+	next.parent = ca
+
+	return context.WithValue(ctx, argsKey{}, next)
 }
 
 // loggerKey
@@ -0,0 +1,163 @@
+package log
+
+import "context"
+
+// boundLoggerKey
+// Структура-ключ для хранения в контексте Logger с уже добавленными аргументами контекста
+type boundLoggerKey struct{}
+
+// boundLogger
+// Logger, созданный With для набора аргументов контекста args на основе base
+type boundLogger struct {
+	// base
+	// Logger, для которого был создан logger: из context.Context или Logger по умолчанию
+	base Logger
+
+	// args
+	// Набор аргументов контекста, добавленный в logger
+	args *contextArgs
+
+	// logger
+	// Logger с добавленными аргументами контекста
+	logger Logger
+
+	// skipped
+	// logger с дополнительным пропуском вызова ContextLogger при определении источника
+	skipped Logger
+}
+
+// CallerSkipper
+// Реализуется Logger, поддерживающими дополнительный пропуск вызовов при определении источника. В отличие от WithSkip,
+// пропуск добавляется к уже установленному, поэтому обертки над Logger и SugaredLogger, Ctx и RecoverPanic могут
+// пропускать свои вызовы независимо друг от друга. Собственные реализации Logger, оборачивающие другой Logger, должны
+// передавать пропуск в него с помощью AddCallerSkip
+type CallerSkipper interface {
+	// WithCallerSkip
+	// Возвращает Logger, пропускающий при определении источника дополнительно skip вызовов
+	WithCallerSkip(skip int) Logger
+}
+
+// AddCallerSkip
+// Возвращает Logger с дополнительным пропуском вызовов при определении источника, если Logger реализует CallerSkipper,
+// иначе возвращает Logger без изменений
+func AddCallerSkip(l Logger, skip int) Logger {
+	if s, ok := l.(CallerSkipper); ok {
+		return s.WithCallerSkip(skip)
+	}
+
+	return l
+}
+
+// With
+// Дополняет набор аргументов context.Context аналогично AddContextArgs и сохраняет в нем Logger, в который эти
+// аргументы уже добавлены с помощью Logger.WithContext. Logger создается на основе Logger из родительского
+// context.Context, поэтому в него добавляются только новые аргументы. Получить Logger позволяют From и Ctx
+func With(ctx context.Context, args ...Arg) context.Context {
+	if ctx == nil || len(args) == 0 {
+		return ctx
+	}
+
+	parent := From(ctx)
+	ctx = AddContextArgs(ctx, args...)
+
+	l := parent.WithContext(ctx)
+
+	return context.WithValue(ctx, boundLoggerKey{}, &boundLogger{
+		base:    defaultLoggerFor(ctx),
+		args:    getContextArgs(ctx),
+		logger:  l,
+		skipped: AddCallerSkip(l, 1),
+	})
+}
+
+// From
+// Возвращает Logger с добавленными аргументами context.Context. Если context.Context создан с помощью With и после
+// этого аргументы и Logger не изменялись, возвращается сохраненный Logger, иначе Logger создается заново на основе
+// Logger из context.Context или Logger по умолчанию
+func From(ctx context.Context) Logger {
+	if b := getBoundLogger(ctx); b != nil {
+		return b.logger
+	}
+
+	return defaultLoggerFor(ctx).WithContext(ctx)
+}
+
+// getBoundLogger
+// Возвращает сохраненный с помощью With Logger, если он соответствует текущему состоянию context.Context
+func getBoundLogger(ctx context.Context) *boundLogger {
+	if ctx == nil {
+		return nil
+	}
+
+	b, ok := ctx.Value(boundLoggerKey{}).(*boundLogger)
+	if !ok || b.args != getContextArgs(ctx) || b.base != defaultLoggerFor(ctx) {
+		return nil
+	}
+
+	return b
+}
+
+// ContextLogger
+// Logger, привязанный к context.Context. Методы записи не требуют передачи context.Context
+type ContextLogger struct {
+	ctx context.Context //nolint:containedctx // ContextLogger is a short-living wrapper bound to ctx
+	l   Logger
+}
+
+// Ctx
+// Возвращает ContextLogger для указанного context.Context на основе From: log.Ctx(ctx).Info("message")
+func Ctx(ctx context.Context) ContextLogger {
+	if b := getBoundLogger(ctx); b != nil {
+		return ContextLogger{ctx: ctx, l: b.skipped}
+	}
+
+	return ContextLogger{ctx: ctx, l: AddCallerSkip(defaultLoggerFor(ctx).WithContext(ctx), 1)}
+}
+
+// Logger
+// Возвращает Logger с добавленными аргументами context.Context
+func (c ContextLogger) Logger() Logger {
+	return From(c.ctx)
+}
+
+// Enabled
+// Сообщает, будет ли записан лог с указанным уровнем
+func (c ContextLogger) Enabled(level Level) bool {
+	return c.l.Enabled(c.ctx, level)
+}
+
+// Trace
+// Записывает TRACE-лог с указанным сообщением и аргументами, а также аргументами, переданными в context.Context
+func (c ContextLogger) Trace(msg string, args ...Arg) {
+	c.l.Trace(c.ctx, msg, args...)
+}
+
+// Debug
+// Записывает DEBUG-лог с указанным сообщением и аргументами, а также аргументами, переданными в context.Context
+func (c ContextLogger) Debug(msg string, args ...Arg) {
+	c.l.Debug(c.ctx, msg, args...)
+}
+
+// Info
+// Записывает INFO-лог с указанным сообщением и аргументами, а также аргументами, переданными в context.Context
+func (c ContextLogger) Info(msg string, args ...Arg) {
+	c.l.Info(c.ctx, msg, args...)
+}
+
+// Warn
+// Записывает WARN-лог с указанным сообщением и аргументами, а также аргументами, переданными в context.Context
+func (c ContextLogger) Warn(err error, msg string, args ...Arg) {
+	c.l.Warn(c.ctx, err, msg, args...)
+}
+
+// Error
+// Записывает ERROR-лог с указанным сообщением и аргументами, а также аргументами, переданными в context.Context
+func (c ContextLogger) Error(err error, msg string, args ...Arg) {
+	c.l.Error(c.ctx, err, msg, args...)
+}
+
+// Write
+// Записывает лог с указанным уровнем, сообщением и аргументами, а также аргументами, переданными в context.Context
+func (c ContextLogger) Write(level Level, msg string, args ...Arg) {
+	c.l.Write(c.ctx, level, msg, args...)
+}
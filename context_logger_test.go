@@ -0,0 +1,104 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_With(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	l := NewLogger(WithFormat(FormatJSON), WithWriter(buf))
+
+	ctx := SetContextLogger(context.Background(), l)
+	ctx = With(ctx, String("component", "api"))
+	ctx = With(ctx, String("request_id", "r-1"))
+
+	assert.Equal(t, []Arg{String("component", "api"), String("request_id", "r-1")}, GetContextArgs(ctx))
+
+	From(ctx).Info(ctx, "bound")
+	Ctx(ctx).Info("sugar", Int("n", 1))
+
+	entries := decodeEntries(t, buf)
+	require.Len(t, entries, 2)
+
+	for _, entry := range entries {
+		assert.Equal(t, "api", entry["component"])
+		assert.Equal(t, "r-1", entry["request_id"])
+	}
+
+	assert.InDelta(t, 1, entries[1]["n"], 0)
+
+	assert.Same(t, ctx, With(ctx))
+	assert.Nil(t, With(nil, String("a", "1"))) //nolint:staticcheck // nil context is handled
+}
+
+func Test_With_NoDuplicates(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	l := NewLogger(WithFormat(FormatLogFmt), WithWriter(buf))
+
+	ctx := With(SetContextLogger(context.Background(), l), String("component", "api"))
+	ctx = With(ctx, String("request_id", "r-1"))
+
+	From(ctx).Info(ctx, "message")
+
+	assert.Equal(t, 1, bytes.Count(buf.Bytes(), []byte("component=api")))
+	assert.Equal(t, 1, bytes.Count(buf.Bytes(), []byte("request_id=r-1")))
+}
+
+func Test_From_Cache(t *testing.T) {
+	t.Parallel()
+
+	l := NewLogger(WithWriter(&bytes.Buffer{}))
+
+	ctx := With(SetContextLogger(context.Background(), l), String("component", "api"))
+	assert.Same(t, From(ctx), From(ctx))
+	assert.Same(t, From(ctx), Ctx(ctx).Logger())
+
+	changed := AddContextArgs(ctx, String("request_id", "r-1"))
+	assert.NotSame(t, From(ctx), From(changed))
+
+	replaced := SetContextLogger(ctx, NewLogger(WithWriter(&bytes.Buffer{})))
+	assert.NotSame(t, From(ctx), From(replaced))
+
+	child, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	assert.Same(t, From(ctx), From(child))
+}
+
+func Test_Ctx_Source(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	l := NewLogger(WithFormat(FormatJSON), WithWriter(buf), WithSource("source"))
+
+	ctx := SetContextLogger(context.Background(), l)
+	Ctx(ctx).Info("unbound")
+	Ctx(With(ctx, String("component", "api"))).Info("bound")
+
+	entries := decodeEntries(t, buf)
+	require.Len(t, entries, 2)
+
+	for _, entry := range entries {
+		assert.Contains(t, fmt.Sprint(entry["source"]), "context_logger_test.go")
+	}
+}
+
+func Test_Ctx_Enabled(t *testing.T) {
+	t.Parallel()
+
+	l := NewLogger(WithWriter(&bytes.Buffer{}), WithLevel("", LevelInfo))
+	ctx := SetContextLogger(context.Background(), l)
+
+	assert.False(t, Ctx(ctx).Enabled(LevelDebug))
+	assert.True(t, Ctx(ctx).Enabled(LevelInfo))
+}
@@ -0,0 +1,130 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	requestIDKey struct{}
+	userIDKey    struct{}
+)
+
+func Test_ContextArgs(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	assert.Nil(t, GetContextArgs(ctx))
+
+	ctx = AddContextArgs(ctx, String("a", "1"))
+	ctx = AddContextArgs(ctx, String("b", "2"))
+	assert.Equal(t, []Arg{String("a", "1"), String("b", "2")}, GetContextArgs(ctx))
+
+	ctx = SetContextArg(ctx, requestIDKey{}, String("request_id", "r-1"))
+	assert.Equal(t, []Arg{String("a", "1"), String("b", "2"), String("request_id", "r-1")}, GetContextArgs(ctx))
+
+	ctx = SetContextArgs(ctx, String("c", "3"))
+	assert.Equal(t, []Arg{String("c", "3"), String("request_id", "r-1")}, GetContextArgs(ctx))
+
+	assert.Nil(t, GetContextArgs(nil)) //nolint:staticcheck // nil context is handled
+	assert.Nil(t, AddContextArgs(nil)) //nolint:staticcheck // nil context is handled
+}
+
+func Test_ContextArg(t *testing.T) {
+	t.Parallel()
+
+	parent := SetContextArg(context.Background(), requestIDKey{}, String("request_id", "r-1"))
+	parent = SetContextArg(parent, userIDKey{}, String("user_id", "u-1"))
+
+	arg, ok := GetContextArg(parent, requestIDKey{})
+	require.True(t, ok)
+	assert.Equal(t, String("request_id", "r-1"), arg)
+
+	child := SetContextArg(parent, requestIDKey{}, String("request_id", "r-2"))
+
+	arg, ok = GetContextArg(child, requestIDKey{})
+	require.True(t, ok)
+	assert.Equal(t, String("request_id", "r-2"), arg)
+	assert.Equal(t, []Arg{String("request_id", "r-2"), String("user_id", "u-1")}, GetContextArgs(child))
+
+	arg, ok = GetContextArg(parent, requestIDKey{})
+	require.True(t, ok)
+	assert.Equal(t, String("request_id", "r-1"), arg)
+
+	deleted := DeleteContextArg(child, requestIDKey{})
+
+	_, ok = GetContextArg(deleted, requestIDKey{})
+	assert.False(t, ok)
+	assert.Equal(t, []Arg{String("user_id", "u-1")}, GetContextArgs(deleted))
+	assert.Equal(t, deleted, DeleteContextArg(deleted, requestIDKey{}))
+
+	_, ok = GetContextArg(parent, nil)
+	assert.False(t, ok)
+
+	_, ok = GetContextArg(context.Background(), requestIDKey{})
+	assert.False(t, ok)
+}
+
+func Test_ContextArg_Logged(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	l := NewLogger(WithFormat(FormatJSON), WithWriter(buf))
+
+	ctx := AddContextArgs(context.Background(), String("component", "api"))
+	ctx = SetContextArg(ctx, requestIDKey{}, String("request_id", "r-1"))
+	l.Info(ctx, "outer")
+
+	ctx = SetContextArg(ctx, requestIDKey{}, String("request_id", "r-2"))
+	l.Info(ctx, "inner")
+
+	entries := decodeEntries(t, buf)
+	require.Len(t, entries, 2)
+
+	assert.Equal(t, "api", entries[0]["component"])
+	assert.Equal(t, "r-1", entries[0]["request_id"])
+	assert.Equal(t, "api", entries[1]["component"])
+	assert.Equal(t, "r-2", entries[1]["request_id"])
+}
+
+func Test_ContextArg_WithContext(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	l := NewLogger(WithFormat(FormatJSON), WithWriter(buf))
+
+	ctx := AddContextArgs(context.Background(), String("component", "api"))
+	ctx = SetContextArg(ctx, requestIDKey{}, String("request_id", "outer"))
+	ctx = SetContextArg(ctx, userIDKey{}, String("user_id", "u-1"))
+	ctx = SetContextLogger(ctx, l.WithContext(ctx))
+
+	override := SetContextArg(ctx, requestIDKey{}, String("request_id", "inner"))
+	From(override).Info(override, "override")
+
+	deleted := DeleteContextArg(ctx, userIDKey{})
+	From(deleted).Info(deleted, "delete")
+
+	added := AddContextArgs(deleted, String("step", "added"))
+	From(added).Info(NoContext, "bound only")
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte{'\n'})
+	require.Len(t, lines, 3)
+
+	assert.Equal(t, 1, bytes.Count(lines[0], []byte(`"component"`)), string(lines[0]))
+	assert.Equal(t, 1, bytes.Count(lines[0], []byte(`"request_id"`)), string(lines[0]))
+	assert.Contains(t, string(lines[0]), `"request_id":"inner"`)
+	assert.Contains(t, string(lines[0]), `"user_id":"u-1"`)
+
+	assert.Equal(t, 1, bytes.Count(lines[1], []byte(`"request_id"`)), string(lines[1]))
+	assert.Contains(t, string(lines[1]), `"request_id":"outer"`)
+	assert.NotContains(t, string(lines[1]), `"user_id"`)
+
+	assert.Equal(t, 1, bytes.Count(lines[2], []byte(`"component"`)), string(lines[2]))
+	assert.Contains(t, string(lines[2]), `"step":"added"`)
+	assert.Contains(t, string(lines[2]), `"request_id":"outer"`)
+	assert.NotContains(t, string(lines[2]), `"user_id"`)
+}
@@ -0,0 +1,196 @@
+package log
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DuplicateKeys
+// Политика обработки аргументов с повторяющимися ключами в итоговом наборе аргументов лога: аргументах Logger,
+// аргументах из context.Context, аргументах вызова и ошибке. Зарезервированные ключи уровня, временной метки,
+// сообщения, источника (если он включен) и имени (для Logger, созданных Logger.Named) всегда записываются Logger,
+// поэтому аргументы с такими ключами считаются повторными. Аргументы с пустым ключом, например, созданные LazyArgs, не
+// обрабатываются
+type DuplicateKeys uint
+
+const (
+	// DuplicateKeysKeep
+	// Записывать все аргументы без изменений, поведение по умолчанию
+	DuplicateKeysKeep DuplicateKeys = iota
+
+	// DuplicateKeysLast
+	// Записывать только последний аргумент с каждым ключом. Аргументы с зарезервированными ключами отбрасываются
+	DuplicateKeysLast
+
+	// DuplicateKeysFirst
+	// Записывать только первый аргумент с каждым ключом. Аргументы с зарезервированными ключами отбрасываются
+	DuplicateKeysFirst
+
+	// DuplicateKeysSuffix
+	// Записывать все аргументы, добавляя к ключу каждого повторного аргумента номер повторения: "key#2", "key#3".
+	// Зарезервированный ключ считается первым повторением
+	DuplicateKeysSuffix
+)
+
+func (d DuplicateKeys) String() string {
+	switch d {
+	case DuplicateKeysKeep:
+		return "keep"
+	case DuplicateKeysLast:
+		return "last"
+	case DuplicateKeysFirst:
+		return "first"
+	case DuplicateKeysSuffix:
+		return "suffix"
+	default:
+		return fmt.Sprintf("DuplicateKeys<%d>", d)
+	}
+}
+
+func (d DuplicateKeys) IsValid() bool {
+	return d >= DuplicateKeysKeep && d <= DuplicateKeysSuffix
+}
+
+// WithDuplicateKeys
+// Определяет политику обработки аргументов с повторяющимися ключами. Для политик, отличных от DuplicateKeysKeep,
+// аргументы, добавленные с помощью Logger.WithArgs, обрабатываются при каждой записи лога
+func WithDuplicateKeys(policy DuplicateKeys) Option {
+	if !policy.IsValid() {
+		return emptyOption
+	}
+
+	return func(o Options) Options {
+		o.DuplicateKeys = policy
+		return o
+	}
+}
+
+// reservedKeys
+// Возвращает ключи, которые всегда записываются Logger с указанными опциями. Ключ имени записывается только
+// Logger, созданным с помощью Logger.Named
+func reservedKeys(opt Options, named bool) []string {
+	keys := make([]string, 0, 5)
+
+	for _, key := range []string{opt.LevelKey, opt.TimeKey, opt.MessageKey} {
+		if len(key) > 0 {
+			keys = append(keys, key)
+		}
+	}
+
+	if opt.AddSource && len(opt.SourceKey) > 0 {
+		keys = append(keys, opt.SourceKey)
+	}
+
+	if named && len(opt.NameKey) > 0 {
+		keys = append(keys, opt.NameKey)
+	}
+
+	return keys
+}
+
+// _duplicateSeparator
+// Разделитель ключа и номера повторения для DuplicateKeysSuffix
+const _duplicateSeparator = "#"
+
+// dedupeArgs
+// Применяет политику обработки повторяющихся ключей к args, изменяя args на месте
+func dedupeArgs(policy DuplicateKeys, reserved []string, args []Arg) []Arg {
+	switch policy {
+	case DuplicateKeysLast:
+		n := 0
+		for i, arg := range args {
+			key := ArgKey(arg)
+			if len(key) > 0 && (slices.Contains(reserved, key) || containsKey(args[i+1:], key)) {
+				continue
+			}
+
+			args[n] = arg
+			n++
+		}
+
+		return args[:n]
+
+	case DuplicateKeysFirst:
+		n := 0
+		for _, arg := range args {
+			key := ArgKey(arg)
+			if len(key) > 0 && (slices.Contains(reserved, key) || containsKey(args[:n], key)) {
+				continue
+			}
+
+			args[n] = arg
+			n++
+		}
+
+		return args[:n]
+
+	case DuplicateKeysSuffix:
+		return suffixArgs(reserved, args)
+
+	case DuplicateKeysKeep:
+		return args
+
+	default:
+		return args
+	}
+}
+
+// suffixArgs
+// Переименовывает повторные аргументы, добавляя номер повторения. Сначала выполняется проверка без выделения памяти,
+// так как в большинстве логов повторений нет
+func suffixArgs(reserved []string, args []Arg) []Arg {
+	duplicated := false
+	for i, arg := range args {
+		key := ArgKey(arg)
+		if len(key) > 0 && (slices.Contains(reserved, key) || containsKey(args[:i], key)) {
+			duplicated = true
+			break
+		}
+	}
+
+	if !duplicated {
+		return args
+	}
+
+	counts := make(map[string]int, len(args)+len(reserved))
+	for _, key := range reserved {
+		counts[key] = 1
+	}
+
+	for i, arg := range args {
+		key := ArgKey(arg)
+		if len(key) == 0 {
+			continue
+		}
+
+		counts[key]++
+		if n := counts[key]; n > 1 {
+			args[i] = renameArg(arg, suffixKey(key, n))
+		}
+	}
+
+	return args
+}
+
+func suffixKey(key string, n int) string {
+	var sb strings.Builder
+
+	sb.Grow(len(key) + len(_duplicateSeparator) + 2)
+	sb.WriteString(key)
+	sb.WriteString(_duplicateSeparator)
+	sb.WriteString(strconv.Itoa(n))
+
+	return sb.String()
+}
+
+func containsKey(args []Arg, key string) bool {
+	for _, arg := range args {
+		if ArgKey(arg) == key {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,162 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// field
+// Пара ключ-значение в порядке записи в JSON
+type field struct {
+	key   string
+	value any
+}
+
+// decodeFields
+// Разбирает лог в формате FormatJSON, сохраняя повторяющиеся ключи и порядок
+func decodeFields(t *testing.T, line string) []field {
+	t.Helper()
+
+	dec := json.NewDecoder(strings.NewReader(line))
+
+	tok, err := dec.Token()
+	require.NoError(t, err)
+	require.Equal(t, json.Delim('{'), tok)
+
+	var fields []field
+	for dec.More() {
+		tok, err = dec.Token()
+		require.NoError(t, err)
+
+		var value any
+		require.NoError(t, dec.Decode(&value))
+
+		fields = append(fields, field{key: tok.(string), value: value}) //nolint:forcetypeassert // object keys are strings
+	}
+
+	return fields
+}
+
+// userFields
+// Возвращает поля лога, за исключением уровня, временной метки и сообщения. Оба драйвера записывают их первыми
+func userFields(fields []field) []field {
+	var (
+		filtered []field
+		builtin  = map[string]bool{LevelKey: true, TimeKey: true, MessageKey: true}
+	)
+
+	for _, f := range fields {
+		if builtin[f.key] {
+			delete(builtin, f.key)
+			continue
+		}
+
+		filtered = append(filtered, f)
+	}
+
+	return filtered
+}
+
+func Test_WithDuplicateKeys(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		policy   DuplicateKeys
+		expected []field
+	}
+
+	testCases := map[string]testCase{
+		"keep": {
+			policy: DuplicateKeysKeep,
+			expected: []field{
+				{key: "request_id", value: "logger"},
+				{key: "request_id", value: "context"},
+				{key: "user", value: "u-1"},
+				{key: "request_id", value: "call"},
+				{key: "level", value: "user-level"},
+				{key: "time", value: "user-time"},
+				{key: "error", value: "user-error"},
+				{key: "error", value: assert.AnError.Error()},
+			},
+		},
+		"last": {
+			policy: DuplicateKeysLast,
+			expected: []field{
+				{key: "user", value: "u-1"},
+				{key: "request_id", value: "call"},
+				{key: "error", value: assert.AnError.Error()},
+			},
+		},
+		"first": {
+			policy: DuplicateKeysFirst,
+			expected: []field{
+				{key: "request_id", value: "logger"},
+				{key: "user", value: "u-1"},
+				{key: "error", value: "user-error"},
+			},
+		},
+		"suffix": {
+			policy: DuplicateKeysSuffix,
+			expected: []field{
+				{key: "request_id", value: "logger"},
+				{key: "request_id#2", value: "context"},
+				{key: "user", value: "u-1"},
+				{key: "request_id#3", value: "call"},
+				{key: "level#2", value: "user-level"},
+				{key: "time#2", value: "user-time"},
+				{key: "error", value: "user-error"},
+				{key: "error#2", value: assert.AnError.Error()},
+			},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			buf := &bytes.Buffer{}
+			l := NewLogger(WithFormat(FormatJSON), WithWriter(buf), WithDuplicateKeys(test.policy)).
+				WithArgs(String("request_id", "logger"))
+
+			ctx := AddContextArgs(context.Background(), String("request_id", "context"), String("user", "u-1"))
+			l.Error(ctx, assert.AnError, "message",
+				String("request_id", "call"), String("level", "user-level"), String("time", "user-time"), String("error", "user-error"))
+
+			fields := decodeFields(t, buf.String())
+			assert.Equal(t, test.expected, userFields(fields))
+
+			builtin := 0
+			for _, f := range fields {
+				switch {
+				case f.key == LevelKey && f.value == "ERROR", f.key == MessageKey && f.value == "message":
+					builtin++
+				}
+			}
+			assert.Equal(t, 2, builtin)
+		})
+	}
+}
+
+func Test_dedupeArgs(t *testing.T) {
+	t.Parallel()
+
+	args := []Arg{String("a", "1"), String("b", "2"), String("a", "3"), String("a", "4"), String("source", "5")}
+	reserved := []string{"source"}
+
+	assert.Equal(t, []Arg{String("b", "2"), String("a", "4")},
+		dedupeArgs(DuplicateKeysLast, reserved, append([]Arg(nil), args...)))
+	assert.Equal(t, []Arg{String("a", "1"), String("b", "2")},
+		dedupeArgs(DuplicateKeysFirst, reserved, append([]Arg(nil), args...)))
+	assert.Equal(t, []Arg{String("a", "1"), String("b", "2"), String("a#2", "3"), String("a#3", "4"), String("source#2", "5")},
+		dedupeArgs(DuplicateKeysSuffix, reserved, append([]Arg(nil), args...)))
+	assert.Equal(t, args, dedupeArgs(DuplicateKeysKeep, reserved, append([]Arg(nil), args...)))
+
+	unique := []Arg{String("a", "1"), String("b", "2")}
+	assert.Equal(t, unique, dedupeArgs(DuplicateKeysSuffix, reserved, unique))
+}
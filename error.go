@@ -0,0 +1,20 @@
+package log
+
+import "errors"
+
+var (
+	ErrInvalidLevel  = errors.New("invalid level")
+	ErrInvalidFormat = errors.New("invalid format")
+
+	// ErrDropEntry
+	// Ошибка, которую Hook возвращает для отмены записи лога
+	ErrDropEntry = errors.New("entry dropped")
+
+	// ErrFallbackWriter
+	// Ошибка основного потока вывода, после которой лог записан в резервный поток
+	ErrFallbackWriter = errors.New("write to fallback writer")
+
+	// ErrPanic
+	// Ошибка, записываемая в лог при перехвате паники
+	ErrPanic = errors.New("panic")
+)
@@ -0,0 +1,141 @@
+package log
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// DefaultFallbackRetry
+// Интервал попыток вернуться к основному потоку вывода после переключения на резервный по умолчанию
+const DefaultFallbackRetry = 5 * time.Second
+
+// WithErrorHandler
+// Устанавливает функцию, вызываемую при ошибке энкодера или потока вывода. По умолчанию ошибки записи
+// драйвера slog игнорируются, а драйвера zap записываются в os.Stderr
+func WithErrorHandler(handler func(err error)) Option {
+	return func(o Options) Options {
+		o.ErrorHandler = handler
+		return o
+	}
+}
+
+// WithFallbackWriter
+// Устанавливает резервный поток вывода, на который Logger переключается после failures последовательных ошибок
+// записи в Options.Writer. Пока используется резервный поток, не чаще одного раза в Options.FallbackRetry
+// выполняется попытка записи в основной поток, при успешной записи Logger возвращается к нему
+func WithFallbackWriter(w io.Writer, failures int) Option {
+	if w == nil {
+		return emptyOption
+	}
+
+	return func(o Options) Options {
+		o.FallbackWriter = w
+		o.FallbackFailures = max(failures, 1)
+		o.fallback = nil
+		return o
+	}
+}
+
+// WithFallbackRetry
+// Определяет интервал попыток вернуться к основному потоку вывода после переключения на резервный
+func WithFallbackRetry(d time.Duration) Option {
+	return func(o Options) Options {
+		o.FallbackRetry = d
+		return o
+	}
+}
+
+// handleError
+// Передает ошибку в Options.ErrorHandler
+func (o *Options) handleError(err error) {
+	if err != nil && o.ErrorHandler != nil {
+		o.ErrorHandler(err)
+	}
+}
+
+// output
+// Возвращает поток вывода Logger с учетом резервного потока. Состояние переключения создается один раз и
+// сохраняется в Options, поэтому Logger, полученные с помощью Logger.WithOptions, Logger.WithArgs и Logger.Named,
+// разделяют счетчик ошибок и переключение на резервный поток
+func (o *Options) output() io.Writer {
+	if o.FallbackWriter == nil {
+		return o.Writer
+	}
+
+	if o.fallback == nil {
+		o.fallback = &fallbackState{}
+	}
+
+	return &fallbackWriter{
+		primary:  o.Writer,
+		fallback: o.FallbackWriter,
+		failures: o.FallbackFailures,
+		retry:    o.FallbackRetry,
+		handle:   o.handleError,
+		state:    o.fallback,
+	}
+}
+
+// fallbackState
+// Состояние переключения между основным и резервным потоками вывода
+type fallbackState struct {
+	mu       sync.Mutex
+	failed   int
+	switched bool
+	probed   time.Time
+}
+
+// fallbackWriter
+// Поток вывода, переключающийся на резервный после нескольких последовательных ошибок записи в основной.
+// Ошибки основного потока, скрытые переключением, передаются в Options.ErrorHandler
+type fallbackWriter struct {
+	primary  io.Writer
+	fallback io.Writer
+	failures int
+	retry    time.Duration
+	handle   func(err error)
+	state    *fallbackState
+}
+
+func (w *fallbackWriter) Write(p []byte) (int, error) {
+	s := w.state
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.switched {
+		n, err := w.primary.Write(p)
+		if err == nil {
+			s.failed = 0
+			return n, nil
+		}
+
+		s.failed++
+		if s.failed < w.failures {
+			return n, err
+		}
+
+		s.switched = true
+		s.probed = time.Now()
+		w.handle(fmt.Errorf("%w: %w", ErrFallbackWriter, err))
+
+		return w.fallback.Write(p)
+	}
+
+	if time.Since(s.probed) >= w.retry {
+		s.probed = time.Now()
+
+		n, err := w.primary.Write(p)
+		if err == nil {
+			s.switched = false
+			s.failed = 0
+			return n, nil
+		}
+
+		w.handle(fmt.Errorf("%w: %w", ErrFallbackWriter, err))
+	}
+
+	return w.fallback.Write(p)
+}
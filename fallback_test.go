@@ -0,0 +1,158 @@
+package log
+
+import (
+	"bytes"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// switchWriter
+// Поток вывода, возвращающий ошибку, пока установлен флаг fail
+type switchWriter struct {
+	bytes.Buffer
+
+	fail atomic.Bool
+}
+
+func (w *switchWriter) Write(p []byte) (int, error) {
+	if w.fail.Load() {
+		return 0, io.ErrShortWrite
+	}
+
+	return w.Buffer.Write(p)
+}
+
+func Test_WithErrorHandler(t *testing.T) {
+	t.Parallel()
+
+	w := &switchWriter{}
+	w.fail.Store(true)
+
+	var errs []error
+	l := NewLogger(WithWriter(w), WithErrorHandler(func(err error) {
+		errs = append(errs, err)
+	}))
+
+	l.Info(NoContext, "first")
+	l.WithArgs(String("key", "value")).Info(NoContext, "second")
+
+	require.Len(t, errs, 2)
+	for _, err := range errs {
+		assert.ErrorIs(t, err, io.ErrShortWrite)
+	}
+
+	w.fail.Store(false)
+	l.Info(NoContext, "third")
+
+	assert.Len(t, errs, 2)
+	assert.Contains(t, w.String(), "third")
+}
+
+func Test_WithFallbackWriter(t *testing.T) {
+	t.Parallel()
+
+	w := &switchWriter{}
+	w.fail.Store(true)
+
+	fallback := &bytes.Buffer{}
+
+	var errs []error
+	l := NewLogger(
+		WithWriter(w),
+		WithFallbackWriter(fallback, 2),
+		WithFallbackRetry(time.Hour),
+		WithErrorHandler(func(err error) {
+			errs = append(errs, err)
+		}),
+	)
+
+	l.Info(NoContext, "lost")
+	require.Len(t, errs, 1)
+	assert.ErrorIs(t, errs[0], io.ErrShortWrite)
+	assert.NotErrorIs(t, errs[0], ErrFallbackWriter)
+	assert.Empty(t, fallback.String())
+
+	l.Info(NoContext, "switched")
+	require.Len(t, errs, 2)
+	assert.ErrorIs(t, errs[1], ErrFallbackWriter)
+	assert.ErrorIs(t, errs[1], io.ErrShortWrite)
+	assert.Contains(t, fallback.String(), "switched")
+
+	w.fail.Store(false)
+	l.Info(NoContext, "fallback")
+	assert.Contains(t, fallback.String(), "fallback")
+	assert.Empty(t, w.String())
+	assert.Len(t, errs, 2)
+}
+
+func Test_WithFallbackWriter_Derived(t *testing.T) {
+	t.Parallel()
+
+	w := &switchWriter{}
+	w.fail.Store(true)
+
+	fallback := &bytes.Buffer{}
+
+	l := NewLogger(WithWriter(w), WithFallbackWriter(fallback, 3), WithFallbackRetry(time.Hour))
+
+	l.Info(NoContext, "first")
+	l.Named("child").Info(NoContext, "second")
+	l.WithOptions(WithLevel(LevelKey, LevelTrace)).Info(NoContext, "third")
+
+	assert.Contains(t, fallback.String(), "third", "failures of derived loggers must be counted together")
+
+	w.fail.Store(false)
+	l.WithArgs(String("key", "value")).Info(NoContext, "fourth")
+	assert.Contains(t, fallback.String(), "fourth", "derived loggers must share switch state")
+	assert.Empty(t, w.String())
+
+	l.WithOptions(WithWriter(w)).Info(NoContext, "fifth")
+	assert.Contains(t, w.String(), "fifth", "new writer must reset switch state")
+}
+
+func Test_FallbackWriter_Recover(t *testing.T) {
+	t.Parallel()
+
+	primary := &switchWriter{}
+	primary.fail.Store(true)
+
+	fallback := &bytes.Buffer{}
+
+	var errs []error
+	w := &fallbackWriter{
+		primary:  primary,
+		fallback: fallback,
+		failures: 1,
+		retry:    0,
+		handle: func(err error) {
+			errs = append(errs, err)
+		},
+		state: &fallbackState{},
+	}
+
+	_, err := w.Write([]byte("a"))
+	require.NoError(t, err)
+
+	_, err = w.Write([]byte("b"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "ab", fallback.String())
+	assert.Len(t, errs, 2, "failed probe of primary writer must be reported")
+
+	primary.fail.Store(false)
+
+	_, err = w.Write([]byte("c"))
+	require.NoError(t, err)
+
+	_, err = w.Write([]byte("d"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "cd", primary.String())
+	assert.Equal(t, "ab", fallback.String())
+	assert.Len(t, errs, 2)
+}
@@ -0,0 +1,91 @@
+package log
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"github.com/anticrew/go-x/xio"
+)
+
+// gelfEncoder
+// Общий для драйверов энкодер формата FormatGELF (GELF 1.1): сообщение записывается в short_message, первая строка
+// многострочного сообщения - в short_message, сообщение целиком - в full_message, уровень - в виде важности syslog,
+// аргументы - в дополнительные поля с префиксом '_'
+type gelfEncoder struct {
+	host string
+}
+
+func newGELFEncoder(Options) *gelfEncoder {
+	host, err := os.Hostname()
+	if err != nil || len(host) == 0 {
+		host = "-"
+	}
+
+	return &gelfEncoder{
+		host: host,
+	}
+}
+
+// encode
+// Записывает лог в buf в виде объекта JSON с переводом строки в конце
+func (e *gelfEncoder) encode(buf *xio.Buffer, level Level, t time.Time, msg string, params []param) {
+	buf.WriteString(`{"version":"1.1","host":`)
+	writeJSONString(buf, e.host)
+
+	short, _, multiline := strings.Cut(msg, "\n")
+	buf.WriteString(`,"short_message":`)
+	writeJSONString(buf, strings.TrimSuffix(short, "\r"))
+
+	if multiline {
+		buf.WriteString(`,"full_message":`)
+		writeJSONString(buf, msg)
+	}
+
+	buf.WriteString(`,"timestamp":`).WriteInt64(t.Unix()).WriteByte('.')
+	writeMillis(buf, t.Nanosecond()/int(time.Millisecond))
+
+	buf.WriteString(`,"level":`).WriteInt64(int64(LevelSeverity(level)))
+
+	for _, p := range params {
+		buf.WriteByte(',')
+		writeJSONString(buf, gelfFieldName(p.key))
+		buf.WriteByte(':')
+		writeGELFValue(buf, p.value)
+	}
+
+	buf.WriteString("}\n")
+}
+
+// writeMillis
+// Записывает миллисекунды тремя цифрами
+func writeMillis(buf *xio.Buffer, ms int) {
+	buf.WriteByte(byte('0' + ms/100)).WriteByte(byte('0' + ms/10%10)).WriteByte(byte('0' + ms%10))
+}
+
+// writeGELFValue
+// Записывает значение дополнительного поля: GELF допускает только числа и строки, остальные значения записываются
+// строкой, составные - строкой с JSON
+func writeGELFValue(buf *xio.Buffer, v any) {
+	if !writeJSONNumber(buf, v) {
+		writeJSONString(buf, syslogValue(v))
+	}
+}
+
+// gelfFieldName
+// Возвращает допустимое название дополнительного поля: '_' и символы из набора [A-Za-z0-9_.-], недопустимые символы
+// заменяются на '_'. Зарезервированное поле _id заменяется на _id_
+func gelfFieldName(key string) string {
+	if key == "id" {
+		return "_id_"
+	}
+
+	return "_" + strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '.', r == '-':
+			return r
+		default:
+			return '_'
+		}
+	}, key)
+}
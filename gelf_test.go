@@ -0,0 +1,86 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/anticrew/go-x/xio"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FormatGELF(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	l := NewLogger(WithFormat(FormatGELF), WithWriter(buf))
+
+	l.Named("svc").Warn(NoContext, errors.New("failed"), "short\nfull",
+		String("id", "1"), String("user id", "u1"), Int("n", 1), Float64("f", 1.5), Float64("nan", math.NaN()),
+		Bool("ok", true), Duration("took", time.Second), Any("m", map[string]int{"k": 1}),
+	)
+	l.Info(NoContext, "plain")
+
+	dec := json.NewDecoder(buf)
+
+	var first map[string]any
+	require.NoError(t, dec.Decode(&first))
+
+	hostname, err := os.Hostname()
+	require.NoError(t, err)
+
+	ts, ok := first["timestamp"].(float64)
+	require.True(t, ok)
+	assert.InDelta(t, float64(time.Now().Unix()), ts, 5)
+	delete(first, "timestamp")
+
+	assert.Equal(t, map[string]any{
+		"version":       "1.1",
+		"host":          hostname,
+		"short_message": "short",
+		"full_message":  "short\nfull",
+		"level":         float64(SeverityWarning),
+		"_id_":          "1",
+		"_user_id":      "u1",
+		"_n":            float64(1),
+		"_f":            1.5,
+		"_nan":          "NaN",
+		"_ok":           "true",
+		"_took":         "1s",
+		"_m":            `{"k":1}`,
+		"_error":        "failed",
+		"_logger":       "svc",
+	}, first)
+
+	var second map[string]any
+	require.NoError(t, dec.Decode(&second))
+	assert.Equal(t, "plain", second["short_message"])
+	assert.NotContains(t, second, "full_message")
+	assert.InDelta(t, float64(SeverityInfo), second["level"], 0)
+}
+
+func Test_writeJSONString(t *testing.T) {
+	t.Parallel()
+
+	for s, want := range map[string]string{
+		"plain":          "plain",
+		`q"b\`:           `q"b\`,
+		"\n\r\t\x00\x1f": "\n\r\t\x00\x1f",
+		"юникод":         "юникод",
+		"bad\xff":        "bad\uFFFD",
+	} {
+		buf := xio.NewBuffer()
+		writeJSONString(buf, s)
+
+		var got string
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &got), buf.String())
+		assert.Equal(t, want, got)
+
+		buf.Dispose()
+	}
+}
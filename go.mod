@@ -3,13 +3,13 @@ module github.com/anticrew/log
 go 1.24.1
 
 require (
+	github.com/anticrew/go-x v0.0.0-20250725232410-641544c0a59c
 	github.com/stretchr/testify v1.10.0
 	github.com/sykesm/zap-logfmt v0.0.4
 	go.uber.org/zap v1.27.0
 )
 
 require (
-	github.com/anticrew/go-x v0.0.0-20250725232410-641544c0a59c // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	go.uber.org/multierr v1.10.0 // indirect
@@ -0,0 +1,147 @@
+package grpclog
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+
+	"github.com/anticrew/log"
+)
+
+// Code
+// Код завершения вызова gRPC, значения совпадают с google.golang.org/grpc/codes.Code
+type Code uint32
+
+// Коды завершения вызова, описание см. в google.golang.org/grpc/codes
+const (
+	OK Code = iota
+	Canceled
+	Unknown
+	InvalidArgument
+	DeadlineExceeded
+	NotFound
+	AlreadyExists
+	PermissionDenied
+	ResourceExhausted
+	FailedPrecondition
+	Aborted
+	OutOfRange
+	Unimplemented
+	Internal
+	Unavailable
+	DataLoss
+	Unauthenticated
+)
+
+// _codeNames
+// Наименования кодов, как в google.golang.org/grpc/codes
+var _codeNames = [...]string{
+	OK:                 "OK",
+	Canceled:           "Canceled",
+	Unknown:            "Unknown",
+	InvalidArgument:    "InvalidArgument",
+	DeadlineExceeded:   "DeadlineExceeded",
+	NotFound:           "NotFound",
+	AlreadyExists:      "AlreadyExists",
+	PermissionDenied:   "PermissionDenied",
+	ResourceExhausted:  "ResourceExhausted",
+	FailedPrecondition: "FailedPrecondition",
+	Aborted:            "Aborted",
+	OutOfRange:         "OutOfRange",
+	Unimplemented:      "Unimplemented",
+	Internal:           "Internal",
+	Unavailable:        "Unavailable",
+	DataLoss:           "DataLoss",
+	Unauthenticated:    "Unauthenticated",
+}
+
+func (c Code) String() string {
+	if int(c) < len(_codeNames) {
+		return _codeNames[c]
+	}
+
+	return "Code(" + strconv.FormatUint(uint64(c), 10) + ")"
+}
+
+// ErrorCode
+// Возвращает код завершения вызова по ошибке: OK для nil, код статуса gRPC, если ошибка или одна из обернутых ошибок
+// реализует метод GRPCStatus() с методом Code(), как ошибки google.golang.org/grpc/status, Canceled и
+// DeadlineExceeded для ошибок context.Context, иначе Unknown
+func ErrorCode(err error) Code {
+	if err == nil {
+		return OK
+	}
+
+	if c, ok := statusCode(err); ok {
+		return c
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return DeadlineExceeded
+	default:
+		return Unknown
+	}
+}
+
+// statusCode
+// Возвращает код статуса gRPC из ошибки с методом GRPCStatus() без зависимости от google.golang.org/grpc
+func statusCode(err error) (Code, bool) {
+	for err != nil {
+		if m := reflect.ValueOf(err).MethodByName("GRPCStatus"); m.IsValid() && m.Type().NumIn() == 0 &&
+			m.Type().NumOut() == 1 {
+			if c, ok := codeOf(m.Call(nil)[0]); ok {
+				return c, true
+			}
+		}
+
+		switch e := err.(type) { //nolint:errorlint // unwrapping manually
+		case interface{ Unwrap() error }:
+			err = e.Unwrap()
+		case interface{ Unwrap() []error }:
+			for _, inner := range e.Unwrap() {
+				if c, ok := statusCode(inner); ok {
+					return c, true
+				}
+			}
+
+			return 0, false
+		default:
+			return 0, false
+		}
+	}
+
+	return 0, false
+}
+
+// codeOf
+// Возвращает результат метода Code() статуса gRPC
+func codeOf(status reflect.Value) (Code, bool) {
+	if status.Kind() == reflect.Pointer && status.IsNil() {
+		return OK, true // status.Status(nil) is OK
+	}
+
+	m := status.MethodByName("Code")
+	if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 || m.Type().Out(0).Kind() != reflect.Uint32 {
+		return 0, false
+	}
+
+	return Code(m.Call(nil)[0].Uint()), true
+}
+
+// CodeLevel
+// Возвращает уровень лога по коду завершения вызова сервера: LevelInfo для OK и ошибок клиента, LevelWarn для
+// нарушений ограничений и состояния, LevelError для ошибок сервера
+func CodeLevel(code Code) log.Level {
+	switch code {
+	case OK, Canceled, InvalidArgument, NotFound, AlreadyExists, Unauthenticated:
+		return log.LevelInfo
+	case DeadlineExceeded, PermissionDenied, ResourceExhausted, FailedPrecondition, Aborted, OutOfRange:
+		return log.LevelWarn
+	default:
+		return log.LevelError
+	}
+}
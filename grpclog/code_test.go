@@ -0,0 +1,90 @@
+package grpclog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/anticrew/log"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeCode
+// Аналог codes.Code
+type fakeCode uint32
+
+// fakeStatus
+// Аналог status.Status
+type fakeStatus struct {
+	code fakeCode
+}
+
+func (s *fakeStatus) Code() fakeCode {
+	if s == nil {
+		return 0
+	}
+
+	return s.code
+}
+
+// fakeStatusError
+// Аналог ошибки, созданной status.Error
+type fakeStatusError struct {
+	s *fakeStatus
+}
+
+func (e *fakeStatusError) Error() string {
+	return "status error"
+}
+
+func (e *fakeStatusError) GRPCStatus() *fakeStatus {
+	return e.s
+}
+
+func Test_ErrorCode(t *testing.T) {
+	t.Parallel()
+
+	notFound := &fakeStatusError{s: &fakeStatus{code: fakeCode(NotFound)}}
+
+	tests := []struct {
+		name string
+		err  error
+		want Code
+	}{
+		{name: "nil", err: nil, want: OK},
+		{name: "status", err: notFound, want: NotFound},
+		{name: "wrapped status", err: fmt.Errorf("call: %w", notFound), want: NotFound},
+		{name: "joined status", err: errors.Join(assert.AnError, notFound), want: NotFound},
+		{name: "nil status", err: &fakeStatusError{}, want: OK},
+		{name: "canceled", err: fmt.Errorf("call: %w", context.Canceled), want: Canceled},
+		{name: "deadline", err: context.DeadlineExceeded, want: DeadlineExceeded},
+		{name: "unknown", err: assert.AnError, want: Unknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, ErrorCode(tt.err))
+		})
+	}
+}
+
+func Test_Code_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "OK", OK.String())
+	assert.Equal(t, "Unauthenticated", Unauthenticated.String())
+	assert.Equal(t, "Code(100)", Code(100).String())
+}
+
+func Test_CodeLevel(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, log.LevelInfo, CodeLevel(OK))
+	assert.Equal(t, log.LevelInfo, CodeLevel(NotFound))
+	assert.Equal(t, log.LevelWarn, CodeLevel(DeadlineExceeded))
+	assert.Equal(t, log.LevelError, CodeLevel(Internal))
+	assert.Equal(t, log.LevelError, CodeLevel(Code(100)))
+}
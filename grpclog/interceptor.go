@@ -0,0 +1,119 @@
+package grpclog
+
+import (
+	"context"
+	"time"
+
+	"github.com/anticrew/log"
+	"github.com/anticrew/log/httplog"
+)
+
+// ServerStream
+// Часть интерфейса grpc.ServerStream, используемая Interceptor
+type ServerStream interface {
+	Context() context.Context
+}
+
+// UnaryHandler
+// Обработчик унарного вызова, совпадает с grpc.UnaryHandler
+type UnaryHandler = func(ctx context.Context, req any) (any, error)
+
+// Interceptor
+// Перехватчик вызовов gRPC, не зависящий от google.golang.org/grpc. Добавляет в context.Context вызова метод, адрес
+// клиента и идентификатор запроса с помощью log.AddContextArgs, сохраняет Logger с помощью log.SetContextLogger и
+// записывает завершение вызова с кодом и длительностью. Подключается к серверу через адаптеры:
+//
+//	grpc.ChainUnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo,
+//		handler grpc.UnaryHandler) (any, error) {
+//		return i.Unary(ctx, req, info.FullMethod, handler)
+//	})
+//
+//	grpc.ChainStreamInterceptor(func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo,
+//		handler grpc.StreamHandler) error {
+//		return i.Stream(ss, info.FullMethod, func(ctx context.Context) error {
+//			return handler(srv, &wrappedStream{ServerStream: ss, ctx: ctx})
+//		})
+//	})
+type Interceptor struct {
+	opt Options
+}
+
+// NewInterceptor
+// Создает Interceptor с указанными опциями
+func NewInterceptor(options ...Option) *Interceptor {
+	return &Interceptor{
+		opt: optionChain(options).apply(defaultOptions()),
+	}
+}
+
+// Unary
+// Выполняет унарный вызов method с помощью handler и записывает его завершение
+func (i *Interceptor) Unary(ctx context.Context, req any, method string, handler UnaryHandler) (any, error) {
+	start := time.Now()
+	ctx, l := i.prepare(ctx, method)
+
+	resp, err := handler(ctx, req)
+	i.finish(ctx, l, "call completed", start, err)
+
+	return resp, err
+}
+
+// Stream
+// Выполняет потоковый вызов method с помощью handler и записывает его завершение. handler получает context.Context
+// с аргументами вызова, который должен быть возвращен методом Context() передаваемого в обработчик потока
+func (i *Interceptor) Stream(ss ServerStream, method string, handler func(ctx context.Context) error) error {
+	start := time.Now()
+	ctx, l := i.prepare(ss.Context(), method)
+
+	err := handler(ctx)
+	i.finish(ctx, l, "stream completed", start, err)
+
+	return err
+}
+
+// prepare
+// Добавляет аргументы вызова в context.Context и сохраняет в нем Logger
+func (i *Interceptor) prepare(ctx context.Context, method string) (context.Context, log.Logger) {
+	id := i.opt.RequestID(ctx)
+	if len(id) == 0 {
+		id = httplog.NewRequestID()
+	}
+
+	args := make([]log.Arg, 0, 3)
+	args = append(args, log.String(MethodKey, method), log.String(RequestIDKey, id))
+
+	if i.opt.Peer != nil {
+		if peer := i.opt.Peer(ctx); len(peer) > 0 {
+			args = append(args, log.String(PeerKey, peer))
+		}
+	}
+
+	l := i.opt.logger(ctx)
+
+	ctx = httplog.SetRequestID(ctx, id)
+	ctx = log.SetContextLogger(ctx, l)
+	ctx = log.AddContextArgs(ctx, args...)
+
+	return ctx, l
+}
+
+// finish
+// Записывает завершение вызова с уровнем по коду завершения. Ошибка с уровнем LevelError или LevelWarn
+// записывается через Logger.Error или Logger.Warn под ключом Options.ErrorKey
+func (i *Interceptor) finish(ctx context.Context, l log.Logger, msg string, start time.Time, err error) {
+	code := i.opt.Code(err)
+
+	level := i.opt.Level(code)
+	args := []log.Arg{log.String(CodeKey, code.String()), log.Duration(DurationKey, time.Since(start))}
+
+	switch {
+	case err == nil:
+		l.Write(ctx, level, msg, args...)
+	case level == log.LevelError:
+		l.Error(ctx, err, msg, args...)
+	case level == log.LevelWarn:
+		l.Warn(ctx, err, msg, args...)
+	default:
+		l.Write(ctx, level, msg, append(args, log.Err(err))...)
+	}
+}
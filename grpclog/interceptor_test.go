@@ -0,0 +1,128 @@
+package grpclog
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/anticrew/log"
+	"github.com/anticrew/log/httplog"
+	"github.com/anticrew/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stream
+// Поток вызова для тестов
+type stream struct {
+	ctx context.Context //nolint:containedctx // mirrors grpc.ServerStream
+}
+
+func (s stream) Context() context.Context {
+	return s.ctx
+}
+
+func Test_Interceptor_Unary(t *testing.T) {
+	t.Parallel()
+
+	l, rec := logtest.NewObserver()
+
+	i := NewInterceptor(WithLogger(l), WithPeer(func(context.Context) string {
+		return "10.0.0.1:5000"
+	}))
+
+	ctx := httplog.SetRequestID(context.Background(), "inbound")
+
+	resp, err := i.Unary(ctx, "req", "/svc.Service/Get", func(ctx context.Context, req any) (any, error) {
+		assert.Equal(t, "inbound", httplog.GetRequestID(ctx))
+		assert.Same(t, l, log.GetContextLogger(ctx))
+
+		log.From(ctx).Info(ctx, "handled")
+
+		return req.(string) + "-resp", nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "req-resp", resp)
+
+	rec.RequireLogged(t, log.LevelInfo, "handled",
+		log.String(MethodKey, "/svc.Service/Get"),
+		log.String(RequestIDKey, "inbound"),
+		log.String(PeerKey, "10.0.0.1:5000"),
+	)
+
+	e := rec.RequireLogged(t, log.LevelInfo, "call completed",
+		log.String(MethodKey, "/svc.Service/Get"),
+		log.String(CodeKey, "OK"),
+	)
+	_, ok := e.Arg(DurationKey)
+	assert.True(t, ok)
+}
+
+func Test_Interceptor_Unary_Error(t *testing.T) {
+	t.Parallel()
+
+	l, rec := logtest.NewObserver()
+
+	i := NewInterceptor(WithLogger(l), WithCode(func(error) Code { return PermissionDenied }))
+
+	_, err := i.Unary(context.Background(), nil, "/svc.Service/Delete", func(context.Context, any) (any, error) {
+		return nil, assert.AnError
+	})
+	require.ErrorIs(t, err, assert.AnError)
+
+	e := rec.RequireLogged(t, log.LevelWarn, "call completed", log.String(CodeKey, "PermissionDenied"))
+
+	id, ok := e.Arg(RequestIDKey)
+	require.True(t, ok)
+	assert.Len(t, id, 32)
+
+	_, ok = e.Arg(log.ErrorKey)
+	assert.True(t, ok)
+}
+
+func Test_Interceptor_ErrorKey(t *testing.T) {
+	t.Parallel()
+
+	var args []log.Arg
+
+	l := log.NewLogger(log.WithWriter(io.Discard), log.WithErrorKey("failure"),
+		log.WithHook(func(_ context.Context, e *log.Entry) error {
+			args = append(args, e.Args...)
+			return nil
+		}),
+	)
+
+	i := NewInterceptor(WithLogger(l), WithCode(func(error) Code { return Internal }))
+
+	_, err := i.Unary(context.Background(), nil, "/svc.Service/Get", func(context.Context, any) (any, error) {
+		return nil, assert.AnError
+	})
+	require.ErrorIs(t, err, assert.AnError)
+
+	assert.Contains(t, args, log.String("failure", assert.AnError.Error()))
+}
+
+func Test_Interceptor_Stream(t *testing.T) {
+	t.Parallel()
+
+	l, rec := logtest.NewObserver()
+
+	i := NewInterceptor(
+		WithRequestID(func(context.Context) string { return "metadata" }),
+		WithLevel(func(Code) log.Level { return log.LevelDebug }),
+	)
+
+	ctx := log.SetContextLogger(context.Background(), l)
+
+	err := i.Stream(stream{ctx: ctx}, "/svc.Service/Watch", func(ctx context.Context) error {
+		assert.Equal(t, "metadata", httplog.GetRequestID(ctx))
+		return context.Canceled
+	})
+	require.ErrorIs(t, err, context.Canceled)
+
+	rec.RequireLogged(t, log.LevelDebug, "stream completed",
+		log.String(MethodKey, "/svc.Service/Watch"),
+		log.String(RequestIDKey, "metadata"),
+		log.String(CodeKey, "Canceled"),
+	)
+}
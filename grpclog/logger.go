@@ -0,0 +1,103 @@
+package grpclog
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/anticrew/log"
+)
+
+// _exit
+// Завершение процесса после Fatal, заменяется в тестах
+var _exit = os.Exit
+
+// _loggerSkip
+// Кол-во вызовов LoggerV2 для пропуска при определении источника: метод LoggerV2 и print
+const _loggerSkip = 2
+
+// LoggerV2
+// Реализация интерфейса grpclog.LoggerV2 поверх Logger для записи внутренних логов grpc-go в формате модуля:
+// grpclog.SetLoggerV2(grpclog.NewLoggerV2(l, 0)). Fatal записывает лог с уровнем LevelError и завершает процесс
+type LoggerV2 struct {
+	l         log.Logger
+	verbosity int
+}
+
+// NewLoggerV2
+// Создает LoggerV2, записывающий логи в l. verbosity определяет наибольший уровень подробности, для которого V
+// возвращает true. Вызовы LoggerV2 пропускаются при определении источника в дополнение к log.WithSkip, если l
+// реализует log.CallerSkipper
+func NewLoggerV2(l log.Logger, verbosity int) *LoggerV2 {
+	return &LoggerV2{
+		l:         log.AddCallerSkip(l, _loggerSkip),
+		verbosity: verbosity,
+	}
+}
+
+func (g *LoggerV2) Info(args ...any) {
+	g.print(log.LevelInfo, fmt.Sprint(args...))
+}
+
+func (g *LoggerV2) Infoln(args ...any) {
+	g.print(log.LevelInfo, sprintln(args))
+}
+
+func (g *LoggerV2) Infof(format string, args ...any) {
+	g.print(log.LevelInfo, fmt.Sprintf(format, args...))
+}
+
+func (g *LoggerV2) Warning(args ...any) {
+	g.print(log.LevelWarn, fmt.Sprint(args...))
+}
+
+func (g *LoggerV2) Warningln(args ...any) {
+	g.print(log.LevelWarn, sprintln(args))
+}
+
+func (g *LoggerV2) Warningf(format string, args ...any) {
+	g.print(log.LevelWarn, fmt.Sprintf(format, args...))
+}
+
+func (g *LoggerV2) Error(args ...any) {
+	g.print(log.LevelError, fmt.Sprint(args...))
+}
+
+func (g *LoggerV2) Errorln(args ...any) {
+	g.print(log.LevelError, sprintln(args))
+}
+
+func (g *LoggerV2) Errorf(format string, args ...any) {
+	g.print(log.LevelError, fmt.Sprintf(format, args...))
+}
+
+func (g *LoggerV2) Fatal(args ...any) {
+	g.print(log.LevelError, fmt.Sprint(args...))
+	_exit(1)
+}
+
+func (g *LoggerV2) Fatalln(args ...any) {
+	g.print(log.LevelError, sprintln(args))
+	_exit(1)
+}
+
+func (g *LoggerV2) Fatalf(format string, args ...any) {
+	g.print(log.LevelError, fmt.Sprintf(format, args...))
+	_exit(1)
+}
+
+// V
+// Сообщает, что уровень подробности l не превышает заданный при создании LoggerV2
+func (g *LoggerV2) V(l int) bool {
+	return l <= g.verbosity
+}
+
+func (g *LoggerV2) print(level log.Level, msg string) {
+	g.l.Write(log.NoContext, level, msg)
+}
+
+// sprintln
+// Форматирует аргументы как fmt.Sprintln без завершающего переноса строки
+func sprintln(args []any) string {
+	return strings.TrimSuffix(fmt.Sprintln(args...), "\n")
+}
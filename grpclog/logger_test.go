@@ -0,0 +1,72 @@
+package grpclog
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anticrew/log"
+	"github.com/anticrew/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_LoggerV2(t *testing.T) { //nolint:paralleltest // replaces _exit
+	var code int
+
+	exit := _exit
+	_exit = func(c int) { code = c }
+
+	t.Cleanup(func() {
+		_exit = exit
+	})
+
+	l, rec := logtest.NewObserver()
+	g := NewLoggerV2(l, 2)
+
+	g.Info("info", 1)
+	g.Infoln("infoln", 2)
+	g.Infof("infof %d", 3)
+	g.Warning("warning")
+	g.Warningln("warningln")
+	g.Warningf("warningf %s", "x")
+	g.Error("error")
+	g.Errorln("errorln")
+	g.Errorf("errorf %v", true)
+
+	require.Equal(t, 9, rec.Len())
+	rec.AssertLogged(t, log.LevelInfo, "info1")
+	rec.AssertLogged(t, log.LevelInfo, "infoln 2")
+	rec.AssertLogged(t, log.LevelInfo, "infof 3")
+	rec.AssertLogged(t, log.LevelWarn, "warning")
+	rec.AssertLogged(t, log.LevelWarn, "warningln")
+	rec.AssertLogged(t, log.LevelWarn, "warningf x")
+	rec.AssertLogged(t, log.LevelError, "error")
+	rec.AssertLogged(t, log.LevelError, "errorln")
+	rec.AssertLogged(t, log.LevelError, "errorf true")
+
+	g.Fatalf("fatal %d", 1)
+	rec.AssertLogged(t, log.LevelError, "fatal 1")
+	assert.Equal(t, 1, code)
+
+	assert.True(t, g.V(2))
+	assert.False(t, g.V(3))
+
+	e := rec.FilterMessage("info1")[0]
+	assert.Contains(t, e.Source, "grpclog/logger_test.go:", "source must point to the LoggerV2 caller")
+}
+
+// grpcInfo
+// Записывает лог через LoggerV2 из вспомогательной функции, пропускаемой с помощью log.WithSkip
+func grpcInfo(g *LoggerV2, msg string) {
+	g.Info(msg)
+}
+
+func Test_LoggerV2_Skip(t *testing.T) {
+	t.Parallel()
+
+	l, rec := logtest.NewObserver(log.WithSkip(1), log.WithSourceFormat(log.SourceFormatFunction))
+	grpcInfo(NewLoggerV2(l, 0), "skipped")
+
+	e := rec.RequireLogged(t, log.LevelInfo, "skipped")
+	assert.True(t, strings.HasSuffix(e.Source, ".Test_LoggerV2_Skip"), e.Source)
+}
@@ -0,0 +1,157 @@
+package grpclog
+
+import (
+	"context"
+
+	"github.com/anticrew/log"
+	"github.com/anticrew/log/httplog"
+)
+
+const (
+	// MethodKey
+	// Ключ для записи полного названия метода
+	MethodKey = "grpc.method"
+
+	// PeerKey
+	// Ключ для записи адреса клиента
+	PeerKey = "peer"
+
+	// RequestIDKey
+	// Ключ для записи идентификатора запроса
+	RequestIDKey = httplog.RequestIDKey
+
+	// CodeKey
+	// Ключ для записи кода завершения вызова
+	CodeKey = "grpc.code"
+
+	// DurationKey
+	// Ключ для записи длительности вызова
+	DurationKey = httplog.DurationKey
+)
+
+// Options
+// Настройки Interceptor
+type Options struct {
+	// Logger
+	// Logger для записи логов вызовов, по умолчанию - Logger из context.Context вызова или Logger по умолчанию
+	Logger log.Logger
+
+	// Peer
+	// Функция получения адреса клиента из context.Context, например, с помощью peer.FromContext, по умолчанию - nil,
+	// адрес не записывается
+	Peer func(ctx context.Context) string
+
+	// RequestID
+	// Функция получения идентификатора запроса из context.Context, например, из metadata, по умолчанию -
+	// httplog.GetRequestID. Если идентификатор не получен, он создается с помощью httplog.NewRequestID
+	RequestID func(ctx context.Context) string
+
+	// Code
+	// Функция получения кода завершения вызова по ошибке, по умолчанию - ErrorCode
+	Code func(err error) Code
+
+	// Level
+	// Функция выбора уровня лога по коду завершения вызова, по умолчанию - CodeLevel
+	Level func(code Code) log.Level
+}
+
+// Option
+// Опция-функция для настройки Interceptor
+type Option func(o Options) Options
+
+// WithLogger
+// Устанавливает Logger для записи логов вызовов
+func WithLogger(l log.Logger) Option {
+	return func(o Options) Options {
+		o.Logger = l
+		return o
+	}
+}
+
+// WithPeer
+// Устанавливает функцию получения адреса клиента
+func WithPeer(fn func(ctx context.Context) string) Option {
+	return func(o Options) Options {
+		o.Peer = fn
+		return o
+	}
+}
+
+// WithRequestID
+// Устанавливает функцию получения идентификатора запроса
+func WithRequestID(fn func(ctx context.Context) string) Option {
+	if fn == nil {
+		return emptyOption
+	}
+
+	return func(o Options) Options {
+		o.RequestID = fn
+		return o
+	}
+}
+
+// WithCode
+// Устанавливает функцию получения кода завершения вызова по ошибке
+func WithCode(fn func(err error) Code) Option {
+	if fn == nil {
+		return emptyOption
+	}
+
+	return func(o Options) Options {
+		o.Code = fn
+		return o
+	}
+}
+
+// WithLevel
+// Устанавливает функцию выбора уровня лога по коду завершения вызова
+func WithLevel(fn func(code Code) log.Level) Option {
+	if fn == nil {
+		return emptyOption
+	}
+
+	return func(o Options) Options {
+		o.Level = fn
+		return o
+	}
+}
+
+// emptyOption
+// Пустая опция, возвращающая исходный Options
+func emptyOption(o Options) Options {
+	return o
+}
+
+type optionChain []Option
+
+func (c optionChain) apply(o Options) Options {
+	for _, opt := range c {
+		o = opt(o)
+	}
+
+	return o
+}
+
+// defaultOptions
+// Опции, заполненные значениями по умолчанию
+func defaultOptions() Options {
+	return Options{
+		RequestID: httplog.GetRequestID,
+		Code:      ErrorCode,
+		Level:     CodeLevel,
+	}
+}
+
+// logger
+// Возвращает Options.Logger, Logger из context.Context или Logger по умолчанию
+func (o *Options) logger(ctx context.Context) log.Logger {
+	if o.Logger != nil {
+		return o.Logger
+	}
+
+	if l := log.GetContextLogger(ctx); l != nil {
+		return l
+	}
+
+	return log.GetDefault()
+}
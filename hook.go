@@ -0,0 +1,236 @@
+package log
+
+import (
+	"context"
+	"io"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/anticrew/log/internal/caller"
+
+	"github.com/anticrew/go-x/pool"
+	"github.com/anticrew/go-x/xio"
+)
+
+// Source
+// Источник записи лога
+type Source struct {
+	// File
+	// Полный путь к файлу
+	File string
+
+	// Line
+	// Номер строки
+	Line int
+
+	// Function
+	// Полное название функции
+	Function string
+}
+
+// IsZero
+// Сообщает, что источник не определен
+func (s Source) IsZero() bool {
+	return len(s.File) == 0 && s.Line == 0 && len(s.Function) == 0
+}
+
+// Entry
+// Лог перед записью, передаваемый в Hook и WriteHook
+type Entry struct {
+	// Level
+	// Уровень лога
+	Level Level
+
+	// Time
+	// Временная метка лога
+	Time time.Time
+
+	// Message
+	// Текстовое сообщение
+	Message string
+
+	// Name
+	// Полное имя Logger, заданное с помощью Logger.Named
+	Name string
+
+	// Args
+	// Итоговый набор аргументов после обработки повторяющихся ключей: аргументы Logger, аргументы из
+	// context.Context, аргументы вызова и ошибка под ключом Options.ErrorKey
+	Args []Arg
+
+	// Source
+	// Источник записи лога, заполняется только при включенной опции WithSource
+	Source Source
+
+	// frame
+	// Кешированный источник, используется для записи, если Hook не изменил Source
+	frame *caller.Frame
+}
+
+// Hook
+// Функция, вызываемая перед записью каждого лога. Может изменить Entry, в том числе аргументы, уровень и
+// сообщение. Возврат ошибки отменяет запись лога, для отмены без ошибки используйте ErrDropEntry. Entry и
+// Entry.Args используются повторно после записи лога, поэтому не должны сохраняться после возврата из Hook
+type Hook func(ctx context.Context, e *Entry) error
+
+// WriteHook
+// Функция, вызываемая после записи каждого лога с байтами, переданными в Options.Writer. Entry и p используются
+// повторно после возврата из WriteHook, поэтому не должны сохраняться. Запись логов через Logger с WriteHook
+// выполняется последовательно
+type WriteHook func(ctx context.Context, e *Entry, p []byte)
+
+// WithHook
+// Добавляет Hook, вызываемый перед записью лога. Hook вызываются в порядке добавления, аргументы Logger
+// обрабатываются при каждой записи лога
+func WithHook(hook Hook) Option {
+	if hook == nil {
+		return emptyOption
+	}
+
+	return func(o Options) Options {
+		o.Hooks = append(slices.Clip(o.Hooks), hook)
+		return o
+	}
+}
+
+// WithWriteHook
+// Добавляет WriteHook, вызываемый после записи лога. WriteHook вызываются в порядке добавления
+func WithWriteHook(hook WriteHook) Option {
+	if hook == nil {
+		return emptyOption
+	}
+
+	return func(o Options) Options {
+		o.WriteHooks = append(slices.Clip(o.WriteHooks), hook)
+		return o
+	}
+}
+
+// hooked
+// Сообщает, что Logger с указанными опциями записывает логи через Entry
+func (o *Options) hooked() bool {
+	return len(o.Hooks) > 0 || len(o.WriteHooks) > 0
+}
+
+// mergeArgs
+// Сообщает, что аргументы Logger с указанными опциями объединяются с остальными аргументами при каждой записи лога,
+// а не добавляются в драйвер заранее
+func (o *Options) mergeArgs() bool {
+	return o.DuplicateKeys != DuplicateKeysKeep || o.hooked()
+}
+
+var _entryPool = pool.NewPool(func() *Entry {
+	return &Entry{}
+})
+
+// newEntry
+// Создает Entry из пула, источник заполняется из frame при включенной опции WithSource
+func newEntry(opt Options, level Level, msg, name string, args []Arg, frame *caller.Frame) *Entry {
+	e := _entryPool.Get()
+
+	e.Level = level
+	e.Time = time.Now()
+	e.Message = msg
+	e.Name = name
+	e.Args = args
+
+	if frame != nil && opt.AddSource {
+		e.frame = frame
+		e.Source = Source{
+			File:     frame.File,
+			Line:     frame.Line,
+			Function: frame.Function,
+		}
+	}
+
+	return e
+}
+
+// releaseEntry
+// Возвращает Entry в пул
+func releaseEntry(e *Entry) {
+	*e = Entry{}
+	_entryPool.Put(e)
+}
+
+// sourceFrame
+// Возвращает источник для записи: кешированный, если Hook не изменил Source, иначе созданный из Source
+func (e *Entry) sourceFrame() *caller.Frame {
+	if e.Source.IsZero() {
+		return nil
+	}
+
+	if f := e.frame; f != nil && f.File == e.Source.File && f.Line == e.Source.Line &&
+		f.Function == e.Source.Function {
+		return f
+	}
+
+	return &caller.Frame{
+		File:     e.Source.File,
+		Line:     e.Source.Line,
+		Function: e.Source.Function,
+	}
+}
+
+// runHooks
+// Вызывает Hook по порядку, возвращает false, если запись лога отменена
+func runHooks(ctx context.Context, hooks []Hook, e *Entry) bool {
+	for _, hook := range hooks {
+		if err := hook(ctx, e); err != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// runWriteHooks
+// Вызывает WriteHook по порядку
+func runWriteHooks(ctx context.Context, hooks []WriteHook, e *Entry, p []byte) {
+	for _, hook := range hooks {
+		hook(ctx, e, p)
+	}
+}
+
+// captureWriter
+// Поток вывода, сохраняющий байты, записанные драйвером, для передачи в WriteHook. Захват выполняется под
+// блокировкой, поэтому записи одного Logger не смешиваются
+type captureWriter struct {
+	out io.Writer
+
+	mu  sync.Mutex
+	buf *xio.Buffer
+}
+
+func newCaptureWriter(opt Options, out io.Writer) *captureWriter {
+	if len(opt.WriteHooks) == 0 {
+		return nil
+	}
+
+	return &captureWriter{
+		out: out,
+	}
+}
+
+func (w *captureWriter) Write(p []byte) (int, error) {
+	if w.buf != nil {
+		w.buf.WriteBytes(p)
+	}
+
+	return w.out.Write(p)
+}
+
+// begin
+// Начинает захват записанных байтов, захват завершается вызовом end
+func (w *captureWriter) begin() *xio.Buffer {
+	w.mu.Lock()
+
+	w.buf = xio.NewBuffer()
+	return w.buf
+}
+
+func (w *captureWriter) end() {
+	w.buf = nil
+	w.mu.Unlock()
+}
@@ -0,0 +1,129 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WithHook(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+
+	var seen []string
+	l := NewLogger(WithFormat(FormatJSON), WithWriter(buf), WithHook(func(_ context.Context, e *Entry) error {
+		for _, arg := range e.Args {
+			seen = append(seen, ArgKey(arg))
+		}
+
+		switch e.Message {
+		case "drop":
+			return ErrDropEntry
+		case "fail":
+			return assert.AnError
+		case "escalate":
+			e.Level = LevelError
+			e.Message = "escalated"
+		}
+
+		e.Args = append(e.Args, String("tag", "hooked"))
+		return nil
+	})).WithArgs(String("logger_arg", "1"))
+
+	ctx := AddContextArgs(context.Background(), String("ctx_arg", "2"))
+
+	l.Warn(ctx, assert.AnError, "message", String("call_arg", "3"))
+	l.Info(ctx, "drop")
+	l.Info(ctx, "fail")
+	l.Info(NoContext, "escalate")
+
+	assert.Equal(t, []string{
+		"logger_arg", "ctx_arg", "call_arg", ErrorKey,
+		"logger_arg", "ctx_arg",
+		"logger_arg", "ctx_arg",
+		"logger_arg",
+	}, seen)
+
+	entries := decodeEntries(t, buf)
+	require.Len(t, entries, 2)
+
+	assert.Equal(t, "message", entries[0][MessageKey])
+	assert.Equal(t, "1", entries[0]["logger_arg"])
+	assert.Equal(t, "2", entries[0]["ctx_arg"])
+	assert.Equal(t, "3", entries[0]["call_arg"])
+	assert.Equal(t, "hooked", entries[0]["tag"])
+
+	assert.Equal(t, "ERROR", entries[1][LevelKey])
+	assert.Equal(t, "escalated", entries[1][MessageKey])
+}
+
+func Test_WithHook_Source(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+
+	var source Source
+	l := NewLogger(WithFormat(FormatJSON), WithWriter(buf), WithSource(""), WithSourceFormat(SourceFormatFileLine),
+		WithHook(func(_ context.Context, e *Entry) error {
+			source = e.Source
+			return nil
+		}),
+	)
+
+	l.Info(NoContext, "original")
+	assert.Equal(t, "github.com/anticrew/log.Test_WithHook_Source", source.Function)
+
+	l.WithOptions(WithHook(func(_ context.Context, e *Entry) error {
+		e.Source = Source{File: "/src/app/api/handler.go", Line: 42, Function: "app/api.Handle"}
+		return nil
+	})).Info(NoContext, "replaced")
+
+	entries := decodeEntries(t, buf)
+	require.Len(t, entries, 2)
+
+	assert.Contains(t, entries[0][SourceKey], "hook_test.go:")
+	assert.Equal(t, "api/handler.go:42", entries[1][SourceKey])
+}
+
+func Test_WithWriteHook(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+
+	var (
+		written [][]byte
+		levels  []Level
+	)
+
+	l := NewLogger(WithFormat(FormatJSON), WithWriter(buf), WithLevel("", LevelInfo),
+		WithWriteHook(func(_ context.Context, e *Entry, p []byte) {
+			written = append(written, bytes.Clone(p))
+			levels = append(levels, e.Level)
+		}),
+	).Named("api")
+
+	l.Debug(NoContext, "disabled")
+	l.Info(NoContext, "first", Int("n", 1))
+	l.Error(NoContext, errors.New("failure"), "second")
+
+	require.Len(t, written, 2)
+	assert.Equal(t, []Level{LevelInfo, LevelError}, levels)
+	assert.Equal(t, buf.String(), string(written[0])+string(written[1]))
+
+	entries := decodeEntries(t, buf)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "api", entries[0][NameKey])
+	assert.InDelta(t, 1, entries[0]["n"], 0)
+}
+
+func Test_WithHook_Nil(t *testing.T) {
+	t.Parallel()
+
+	opt := optionChain{WithHook(nil), WithWriteHook(nil)}.apply(defaultOptions())
+	assert.False(t, opt.hooked())
+}
@@ -0,0 +1,134 @@
+package httplog
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/anticrew/log"
+	"github.com/anticrew/log/internal/caller"
+)
+
+// requestIDKey
+// Структура-ключ для хранения идентификатора запроса в контексте
+type requestIDKey struct{}
+
+// GetRequestID
+// Возвращает идентификатор запроса, сохраненный в context.Context с помощью SetRequestID или Middleware, или пустую
+// строку
+func GetRequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// SetRequestID
+// Возвращает context.Context, содержащий указанный идентификатор запроса
+func SetRequestID(ctx context.Context, id string) context.Context {
+	if ctx == nil {
+		return nil
+	}
+
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// ErrPanic
+// Ошибка, записываемая в лог при панике в обработчике запроса
+var ErrPanic = log.ErrPanic
+
+// Middleware
+// Возвращает middleware, записывающий в лог завершение каждого запроса. Идентификатор запроса читается из заголовка
+// Options.RequestIDHeader или создается заново и возвращается в том же заголовке ответа. Идентификатор, метод, путь и
+// адрес клиента добавляются в context.Context запроса с помощью log.AddContextArgs, а Logger сохраняется с помощью
+// log.SetContextLogger, поэтому логи обработчика содержат те же аргументы. Паника в обработчике записывается в лог с
+// уровнем LevelError и стеком вызовов, клиенту возвращается код 500
+func Middleware(options ...Option) func(http.Handler) http.Handler {
+	opt := optionChain(options).apply(defaultOptions())
+
+	return func(next http.Handler) http.Handler {
+		return &handler{
+			next: next,
+			opt:  opt,
+		}
+	}
+}
+
+// handler
+// Обработчик запросов, созданный Middleware
+type handler struct {
+	next http.Handler
+	opt  Options
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	ctx := r.Context()
+
+	id := r.Header.Get(h.opt.RequestIDHeader)
+	if len(id) == 0 {
+		id = h.opt.RequestID()
+	}
+
+	w.Header().Set(h.opt.RequestIDHeader, id)
+
+	l := h.opt.logger(ctx)
+
+	ctx = SetRequestID(ctx, id)
+	ctx = log.SetContextLogger(ctx, l)
+	ctx = log.AddContextArgs(ctx,
+		log.String(RequestIDKey, id),
+		log.String(MethodKey, r.Method),
+		log.String(PathKey, r.URL.Path),
+		log.String(RemoteKey, r.RemoteAddr),
+	)
+
+	rw := &responseWriter{
+		ResponseWriter: w,
+	}
+
+	defer func() {
+		rec := recover()
+		if rec == nil {
+			return
+		}
+
+		if rec == http.ErrAbortHandler { //nolint:errorlint,err113 // http.ErrAbortHandler is compared by identity
+			panic(rec)
+		}
+
+		if !rw.wroteHeader && !rw.hijacked {
+			rw.WriteHeader(http.StatusInternalServerError)
+		}
+
+		l.Error(ctx, fmt.Errorf("%w: %v", ErrPanic, rec), "request panicked",
+			log.Int(StatusKey, rw.Status()),
+			log.Int64(BytesKey, rw.bytes),
+			log.Duration(DurationKey, time.Since(start)),
+			log.String(StackKey, caller.Stack(2)), // skip deferred function and runtime.gopanic
+		)
+	}()
+
+	h.next.ServeHTTP(rw, r.WithContext(ctx))
+
+	if _, ok := h.opt.SkipPaths[r.URL.Path]; ok {
+		return
+	}
+
+	status := rw.Status()
+
+	args := []log.Arg{
+		log.Int(StatusKey, status),
+		log.Int64(BytesKey, rw.bytes),
+		log.Duration(DurationKey, time.Since(start)),
+	}
+
+	if h.opt.Headers {
+		args = append(args, h.opt.headersArg(r.Header))
+	}
+
+	l.Write(ctx, h.opt.Level(status), "request completed", args...)
+}
@@ -0,0 +1,176 @@
+package httplog
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/anticrew/log"
+	"github.com/anticrew/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Middleware(t *testing.T) {
+	t.Parallel()
+
+	l, rec := logtest.NewObserver()
+
+	h := Middleware(WithLogger(l), WithRequestID(func() string { return "generated" }))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "generated", GetRequestID(r.Context()))
+
+			log.From(r.Context()).Info(r.Context(), "handled")
+
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte("body"))
+		}),
+	)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/items?id=1", nil)
+	r.RemoteAddr = "10.0.0.1:1234"
+
+	h.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "generated", w.Header().Get(RequestIDHeader))
+
+	rec.RequireLogged(t, log.LevelInfo, "handled",
+		log.String(RequestIDKey, "generated"),
+		log.String(MethodKey, http.MethodPost),
+		log.String(PathKey, "/items"),
+		log.String(RemoteKey, "10.0.0.1:1234"),
+	)
+
+	e := rec.RequireLogged(t, log.LevelInfo, "request completed",
+		log.String(RequestIDKey, "generated"),
+		log.Int(StatusKey, http.StatusCreated),
+		log.Int64(BytesKey, 4),
+	)
+	assert.True(t, e.HasArg(MethodKey, http.MethodPost))
+	_, ok := e.Arg(DurationKey)
+	assert.True(t, ok)
+}
+
+func Test_Middleware_RequestIDHeader(t *testing.T) {
+	t.Parallel()
+
+	l, rec := logtest.NewObserver()
+
+	h := Middleware(WithLogger(l), WithRequestIDHeader("x-trace-id"))(http.NotFoundHandler())
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/missing", nil)
+	r.Header.Set("X-Trace-Id", "inbound")
+
+	h.ServeHTTP(w, r)
+
+	assert.Equal(t, "inbound", w.Header().Get("X-Trace-Id"))
+	rec.RequireLogged(t, log.LevelWarn, "request completed",
+		log.String(RequestIDKey, "inbound"),
+		log.Int(StatusKey, http.StatusNotFound),
+	)
+}
+
+func Test_Middleware_SkipPaths(t *testing.T) {
+	t.Parallel()
+
+	l, rec := logtest.NewObserver()
+
+	h := Middleware(WithLogger(l), WithSkipPaths("/healthz"), WithSkipPaths("/readyz"))(
+		http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}),
+	)
+
+	for _, path := range []string{"/healthz", "/readyz", "/api"} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	require.Equal(t, 1, rec.Len())
+	rec.RequireLogged(t, log.LevelInfo, "request completed", log.String(PathKey, "/api"), log.Int(StatusKey, 200))
+}
+
+func panicHandler(http.ResponseWriter, *http.Request) {
+	panic("boom")
+}
+
+func Test_Middleware_Panic(t *testing.T) {
+	t.Parallel()
+
+	l, rec := logtest.NewObserver()
+
+	h := Middleware(WithLogger(l))(http.HandlerFunc(panicHandler))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	e := rec.RequireLogged(t, log.LevelError, "request panicked",
+		log.String(log.ErrorKey, "panic: boom"),
+		log.Int(StatusKey, http.StatusInternalServerError),
+	)
+
+	stack, ok := e.Arg(StackKey)
+	require.True(t, ok)
+	assert.True(t, strings.HasPrefix(stack.(string), "github.com/anticrew/log/httplog.panicHandler\n"), stack)
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		Middleware(WithLogger(l))(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+			panic(http.ErrAbortHandler)
+		})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
+
+func Test_StatusLevel(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, log.LevelInfo, StatusLevel(http.StatusOK))
+	assert.Equal(t, log.LevelInfo, StatusLevel(http.StatusFound))
+	assert.Equal(t, log.LevelWarn, StatusLevel(http.StatusBadRequest))
+	assert.Equal(t, log.LevelError, StatusLevel(http.StatusBadGateway))
+}
+
+// hijackRecorder
+// httptest.ResponseRecorder с поддержкой http.Hijacker
+type hijackRecorder struct {
+	*httptest.ResponseRecorder
+
+	conn net.Conn
+}
+
+func (r *hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return r.conn, nil, nil
+}
+
+func Test_Middleware_FlusherHijacker(t *testing.T) {
+	t.Parallel()
+
+	l, rec := logtest.NewObserver()
+
+	server, client := net.Pipe()
+	defer client.Close()
+
+	h := Middleware(WithLogger(l))(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		require.NoError(t, http.NewResponseController(w).Flush())
+
+		conn, _, err := w.(http.Hijacker).Hijack()
+		require.NoError(t, err)
+		assert.Same(t, server, conn)
+	}))
+
+	w := &hijackRecorder{ResponseRecorder: httptest.NewRecorder(), conn: server}
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ws", nil))
+
+	assert.True(t, w.Flushed)
+	rec.RequireLogged(t, log.LevelInfo, "request completed", log.Int(StatusKey, http.StatusOK))
+
+	h = Middleware(WithLogger(l))(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _, err := w.(http.Hijacker).Hijack()
+		require.ErrorIs(t, err, http.ErrNotSupported)
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ws", nil))
+}
@@ -0,0 +1,284 @@
+package httplog
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/anticrew/log"
+)
+
+const (
+	// RequestIDHeader
+	// Заголовок с идентификатором запроса по умолчанию
+	RequestIDHeader = "X-Request-Id"
+
+	// RequestIDKey
+	// Ключ для записи идентификатора запроса
+	RequestIDKey = "request_id"
+
+	// MethodKey
+	// Ключ для записи метода запроса
+	MethodKey = "method"
+
+	// ClientMethodKey
+	// Ключ для записи метода исходящего запроса
+	ClientMethodKey = "client_method"
+
+	// ClientHostKey
+	// Ключ для записи хоста исходящего запроса
+	ClientHostKey = "client_host"
+
+	// ClientPathKey
+	// Ключ для записи пути исходящего запроса
+	ClientPathKey = "client_path"
+
+	// PathKey
+	// Ключ для записи пути запроса
+	PathKey = "path"
+
+	// RemoteKey
+	// Ключ для записи адреса клиента
+	RemoteKey = "remote"
+
+	// StatusKey
+	// Ключ для записи кода ответа
+	StatusKey = "status"
+
+	// BytesKey
+	// Ключ для записи кол-ва байтов тела ответа
+	BytesKey = "bytes"
+
+	// DurationKey
+	// Ключ для записи длительности обработки запроса
+	DurationKey = "duration"
+
+	// StackKey
+	// Ключ для записи стека вызовов при панике
+	StackKey = log.StackKey
+
+	// HeadersKey
+	// Ключ для записи заголовков запроса
+	HeadersKey = "headers"
+
+	// Redacted
+	// Значение, записываемое вместо скрытых заголовков
+	Redacted = "[REDACTED]"
+)
+
+// Options
+// Настройки Middleware
+type Options struct {
+	// Logger
+	// Logger для записи логов запросов, по умолчанию - Logger из context.Context запроса или Logger по умолчанию
+	Logger log.Logger
+
+	// RequestIDHeader
+	// Заголовок, из которого читается и в который записывается идентификатор запроса, по умолчанию - RequestIDHeader
+	RequestIDHeader string
+
+	// RequestID
+	// Функция создания идентификатора запроса, если он не передан в заголовке, по умолчанию - NewRequestID
+	RequestID func() string
+
+	// SkipPaths
+	// Пути запросов, завершение которых не записывается в лог, например, проверки состояния, по умолчанию - пустой
+	// набор
+	SkipPaths map[string]struct{}
+
+	// Level
+	// Функция выбора уровня лога по коду ответа, по умолчанию - StatusLevel
+	Level func(status int) log.Level
+
+	// Headers
+	// Флаг записи заголовков запроса под ключом HeadersKey
+	Headers bool
+
+	// RedactHeaders
+	// Заголовки, значения которых заменяются на Redacted при записи, в каноническом виде, по умолчанию -
+	// Authorization, Proxy-Authorization, Cookie и Set-Cookie
+	RedactHeaders map[string]struct{}
+}
+
+// Option
+// Опция-функция для настройки Middleware
+type Option func(o Options) Options
+
+// WithLogger
+// Устанавливает Logger для записи логов запросов
+func WithLogger(l log.Logger) Option {
+	return func(o Options) Options {
+		o.Logger = l
+		return o
+	}
+}
+
+// WithRequestIDHeader
+// Устанавливает заголовок с идентификатором запроса
+func WithRequestIDHeader(header string) Option {
+	if len(header) == 0 {
+		return emptyOption
+	}
+
+	return func(o Options) Options {
+		o.RequestIDHeader = http.CanonicalHeaderKey(header)
+		return o
+	}
+}
+
+// WithRequestID
+// Устанавливает функцию создания идентификатора запроса
+func WithRequestID(fn func() string) Option {
+	if fn == nil {
+		return emptyOption
+	}
+
+	return func(o Options) Options {
+		o.RequestID = fn
+		return o
+	}
+}
+
+// WithSkipPaths
+// Добавляет пути запросов, завершение которых не записывается в лог
+func WithSkipPaths(paths ...string) Option {
+	return func(o Options) Options {
+		skip := make(map[string]struct{}, len(o.SkipPaths)+len(paths))
+		for path := range o.SkipPaths {
+			skip[path] = struct{}{}
+		}
+
+		for _, path := range paths {
+			skip[path] = struct{}{}
+		}
+
+		o.SkipPaths = skip
+		return o
+	}
+}
+
+// WithLevel
+// Устанавливает функцию выбора уровня лога по коду ответа
+func WithLevel(fn func(status int) log.Level) Option {
+	if fn == nil {
+		return emptyOption
+	}
+
+	return func(o Options) Options {
+		o.Level = fn
+		return o
+	}
+}
+
+// WithHeaders
+// Включает запись заголовков запроса
+func WithHeaders() Option {
+	return func(o Options) Options {
+		o.Headers = true
+		return o
+	}
+}
+
+// WithRedactHeaders
+// Добавляет заголовки, значения которых заменяются на Redacted при записи
+func WithRedactHeaders(headers ...string) Option {
+	return func(o Options) Options {
+		redact := make(map[string]struct{}, len(o.RedactHeaders)+len(headers))
+		for header := range o.RedactHeaders {
+			redact[header] = struct{}{}
+		}
+
+		for _, header := range headers {
+			redact[http.CanonicalHeaderKey(header)] = struct{}{}
+		}
+
+		o.RedactHeaders = redact
+		return o
+	}
+}
+
+// StatusLevel
+// Возвращает уровень лога по классу кода ответа: 5xx - LevelError, 4xx - LevelWarn, остальные - LevelInfo
+func StatusLevel(status int) log.Level {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return log.LevelError
+	case status >= http.StatusBadRequest:
+		return log.LevelWarn
+	default:
+		return log.LevelInfo
+	}
+}
+
+// NewRequestID
+// Возвращает случайный идентификатор запроса из 32 шестнадцатеричных символов
+func NewRequestID() string {
+	var id [16]byte
+	_, _ = rand.Read(id[:])
+
+	return hex.EncodeToString(id[:])
+}
+
+// emptyOption
+// Пустая опция, возвращающая исходный Options
+func emptyOption(o Options) Options {
+	return o
+}
+
+type optionChain []Option
+
+func (c optionChain) apply(o Options) Options {
+	for _, opt := range c {
+		o = opt(o)
+	}
+
+	return o
+}
+
+// defaultOptions
+// Опции, заполненные значениями по умолчанию
+func defaultOptions() Options {
+	return Options{
+		RequestIDHeader: RequestIDHeader,
+		RequestID:       NewRequestID,
+		Level:           StatusLevel,
+		RedactHeaders: map[string]struct{}{
+			"Authorization":       {},
+			"Proxy-Authorization": {},
+			"Cookie":              {},
+			"Set-Cookie":          {},
+		},
+	}
+}
+
+// logger
+// Возвращает Options.Logger, Logger из context.Context или Logger по умолчанию
+func (o *Options) logger(ctx context.Context) log.Logger {
+	if o.Logger != nil {
+		return o.Logger
+	}
+
+	if l := log.GetContextLogger(ctx); l != nil {
+		return l
+	}
+
+	return log.GetDefault()
+}
+
+// headersArg
+// Возвращает аргумент с заголовками запроса, значения заголовков из Options.RedactHeaders заменяются на Redacted
+func (o *Options) headersArg(h http.Header) log.Arg {
+	headers := make(map[string]string, len(h))
+	for key, values := range h {
+		if _, ok := o.RedactHeaders[http.CanonicalHeaderKey(key)]; ok {
+			headers[key] = Redacted
+			continue
+		}
+
+		headers[key] = strings.Join(values, ", ")
+	}
+
+	return log.Any(HeadersKey, headers)
+}
@@ -0,0 +1,112 @@
+package httplog
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/anticrew/log"
+)
+
+// levelKey
+// Структура-ключ для хранения уровня лога исходящих запросов в контексте
+type levelKey struct{}
+
+// SetLevel
+// Возвращает context.Context, содержащий уровень, с которым Transport записывает завершение исходящих запросов
+// вместо Options.Level, например, log.LevelDebug для частых служебных вызовов
+func SetLevel(ctx context.Context, level log.Level) context.Context {
+	if ctx == nil {
+		return nil
+	}
+
+	return context.WithValue(ctx, levelKey{}, level)
+}
+
+// GetLevel
+// Возвращает уровень, сохраненный в context.Context с помощью SetLevel, и статус получения
+func GetLevel(ctx context.Context) (log.Level, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+
+	level, ok := ctx.Value(levelKey{}).(log.Level)
+	return level, ok
+}
+
+// Transport
+// Возвращает http.RoundTripper, записывающий в лог каждый исходящий запрос с методом, хостом, путем, кодом ответа,
+// длительностью и ошибкой. Метод, хост и путь записываются под ключами ClientMethodKey, ClientHostKey и
+// ClientPathKey, чтобы не пересекаться с аргументами входящего запроса из Middleware. Если base не указан, используется
+// http.DefaultTransport. Logger берется из Options.Logger, context.Context запроса (log.GetContextLogger) или Logger
+// по умолчанию, аргументы context.Context запроса добавляются к логу. Уровень определяется с помощью SetLevel, а при
+// его отсутствии - Options.Level по коду ответа или LevelError при ошибке. Идентификатор запроса из context.Context
+// (GetRequestID) передается в заголовке Options.RequestIDHeader, если он не установлен
+func Transport(base http.RoundTripper, options ...Option) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &transport{
+		base: base,
+		opt:  optionChain(options).apply(defaultOptions()),
+	}
+}
+
+// transport
+// http.RoundTripper, созданный Transport
+type transport struct {
+	base http.RoundTripper
+	opt  Options
+}
+
+func (t *transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	ctx := r.Context()
+
+	if id := GetRequestID(ctx); len(id) > 0 && len(r.Header.Get(t.opt.RequestIDHeader)) == 0 {
+		r = r.Clone(ctx)
+		r.Header.Set(t.opt.RequestIDHeader, id)
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(r)
+	duration := time.Since(start)
+
+	args := make([]log.Arg, 0, 7)
+	args = append(args,
+		log.String(ClientMethodKey, r.Method),
+		log.String(ClientHostKey, r.URL.Host),
+		log.String(ClientPathKey, r.URL.Path),
+	)
+
+	level, msg := log.LevelError, "outbound request failed"
+	if err != nil {
+		args = append(args, log.Duration(DurationKey, duration))
+	} else {
+		level, msg = t.opt.Level(resp.StatusCode), "outbound request completed"
+		args = append(args, log.Int(StatusKey, resp.StatusCode), log.Duration(DurationKey, duration))
+	}
+
+	if l, ok := GetLevel(ctx); ok {
+		level = l
+	}
+
+	if t.opt.Headers {
+		args = append(args, t.opt.headersArg(r.Header))
+	}
+
+	l := t.opt.logger(ctx)
+
+	switch {
+	case err == nil:
+		l.Write(ctx, level, msg, args...)
+	case level == log.LevelError:
+		l.Error(ctx, err, msg, args...)
+	case level == log.LevelWarn:
+		l.Warn(ctx, err, msg, args...)
+	default:
+		l.Write(ctx, level, msg, append(args, log.Err(err))...)
+	}
+
+	return resp, err
+}
@@ -0,0 +1,183 @@
+package httplog
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/anticrew/log"
+	"github.com/anticrew/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// roundTripFunc
+// Функция, реализующая http.RoundTripper
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func Test_Transport(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "inbound", r.Header.Get(RequestIDHeader))
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	l, rec := logtest.NewObserver()
+
+	ctx := log.SetContextLogger(context.Background(), l)
+	ctx = log.AddContextArgs(ctx, log.String("caller", "test"))
+	ctx = SetRequestID(ctx, "inbound")
+
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/status?x=1", nil)
+	require.NoError(t, err)
+
+	r.Header.Set("Authorization", "Bearer secret")
+	r.Header.Set("X-Api-Key", "secret")
+	r.Header.Set("Accept", "text/plain")
+
+	client := &http.Client{Transport: Transport(nil, WithHeaders(), WithRedactHeaders("x-api-key"))}
+
+	resp, err := client.Do(r)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	assert.Empty(t, r.Header.Get(RequestIDHeader), "request must not be modified")
+
+	e := rec.RequireLogged(t, log.LevelError, "outbound request completed",
+		log.String("caller", "test"),
+		log.String(ClientMethodKey, http.MethodGet),
+		log.String(ClientHostKey, strings.TrimPrefix(server.URL, "http://")),
+		log.String(ClientPathKey, "/status"),
+		log.Int(StatusKey, http.StatusServiceUnavailable),
+	)
+
+	_, ok := e.Arg(DurationKey)
+	assert.True(t, ok)
+
+	headers, ok := e.Arg(HeadersKey)
+	require.True(t, ok)
+	assert.Equal(t, map[string]string{
+		"Authorization": Redacted,
+		"X-Api-Key":     Redacted,
+		"Accept":        "text/plain",
+		RequestIDHeader: "inbound",
+	}, headers)
+}
+
+func Test_Transport_Error(t *testing.T) {
+	t.Parallel()
+
+	l, rec := logtest.NewObserver()
+
+	tr := Transport(roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return nil, assert.AnError
+	}), WithLogger(l))
+
+	r := httptest.NewRequest(http.MethodPost, "http://example.com/items", nil)
+
+	_, err := tr.RoundTrip(r) //nolint:bodyclose // response is nil
+	require.ErrorIs(t, err, assert.AnError)
+
+	e := rec.RequireLogged(t, log.LevelError, "outbound request failed",
+		log.String(ClientMethodKey, http.MethodPost),
+		log.String(ClientHostKey, "example.com"),
+		log.String(ClientPathKey, "/items"),
+	)
+	assert.False(t, e.HasArg(StatusKey, 0))
+	_, ok := e.Arg(log.ErrorKey)
+	assert.True(t, ok)
+	_, ok = e.Arg(HeadersKey)
+	assert.False(t, ok)
+}
+
+func Test_Transport_ErrorKey(t *testing.T) {
+	t.Parallel()
+
+	var args []log.Arg
+
+	l := log.NewLogger(log.WithWriter(io.Discard), log.WithErrorKey("failure"),
+		log.WithHook(func(_ context.Context, e *log.Entry) error {
+			args = append(args, e.Args...)
+			return nil
+		}),
+	)
+
+	tr := Transport(roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return nil, assert.AnError
+	}), WithLogger(l))
+
+	_, err := tr.RoundTrip(httptest.NewRequest(http.MethodGet, "http://example.com/", nil)) //nolint:bodyclose // response is nil
+	require.ErrorIs(t, err, assert.AnError)
+
+	assert.Contains(t, args, log.String("failure", assert.AnError.Error()))
+}
+
+func Test_Transport_Inbound(t *testing.T) {
+	t.Parallel()
+
+	l, rec := logtest.NewObserver()
+
+	client := &http.Client{Transport: Transport(roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: r}, nil
+	}), WithLogger(l))}
+
+	handler := Middleware(WithLogger(l))(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		req, err := http.NewRequestWithContext(r.Context(), http.MethodPost, "http://example.com/items", nil)
+		require.NoError(t, err)
+
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders", nil))
+
+	e := rec.RequireLogged(t, log.LevelInfo, "outbound request completed",
+		log.String(MethodKey, http.MethodGet),
+		log.String(PathKey, "/orders"),
+		log.String(ClientMethodKey, http.MethodPost),
+		log.String(ClientHostKey, "example.com"),
+		log.String(ClientPathKey, "/items"),
+	)
+
+	keys := make(map[string]int)
+	for _, a := range e.Args {
+		keys[a.Key]++
+	}
+
+	assert.Equal(t, 1, keys[MethodKey])
+	assert.Equal(t, 1, keys[PathKey])
+}
+
+func Test_Transport_Level(t *testing.T) {
+	t.Parallel()
+
+	l, rec := logtest.NewObserver()
+
+	tr := Transport(roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Request: r}, nil
+	}), WithLogger(l))
+
+	ctx := SetLevel(context.Background(), log.LevelDebug)
+
+	_, err := tr.RoundTrip(httptest.NewRequest(http.MethodGet, "http://example.com/", nil).WithContext(ctx)) //nolint:bodyclose // no body
+	require.NoError(t, err)
+
+	rec.RequireLogged(t, log.LevelDebug, "outbound request completed", log.Int(StatusKey, http.StatusOK))
+
+	level, ok := GetLevel(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, log.LevelDebug, level)
+
+	_, ok = GetLevel(context.Background())
+	assert.False(t, ok)
+}
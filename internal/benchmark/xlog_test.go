@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/anticrew/log"
+	"github.com/anticrew/log/internal/caller"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -62,6 +63,55 @@ func Benchmark_Xlog(b *testing.B) {
 	})
 }
 
+func Benchmark_Xlog_Source(b *testing.B) {
+	b.Run("none", func(b *testing.B) {
+		l := log.NewLogger(log.WithFormat(log.FormatJSON), log.WithWriter(io.Discard),
+			log.WithLevel("", log.LevelInfo))
+
+		b.ResetTimer()
+		b.ReportAllocs()
+
+		for b.Loop() {
+			benchmarkXlog(l)
+		}
+	})
+
+	formats := []log.SourceFormat{
+		log.SourceFormatShort,
+		log.SourceFormatFull,
+		log.SourceFormatFileLine,
+		log.SourceFormatFunction,
+		log.SourceFormatModule,
+		log.SourceFormatObject,
+	}
+
+	for _, format := range formats {
+		l := log.NewLogger(log.WithFormat(log.FormatJSON), log.WithWriter(io.Discard),
+			log.WithLevel("", log.LevelInfo), log.WithSource(log.SourceKey), log.WithSourceFormat(format))
+
+		b.Run(format.String(), func(b *testing.B) {
+			b.Run("cached", func(b *testing.B) {
+				b.ReportAllocs()
+
+				for b.Loop() {
+					benchmarkXlog(l)
+				}
+			})
+
+			// Базовый уровень: адрес вызова разрешается с помощью runtime.CallersFrames при каждой записи
+			b.Run("uncached", func(b *testing.B) {
+				defer caller.DisableCache()()
+
+				b.ReportAllocs()
+
+				for b.Loop() {
+					benchmarkXlog(l)
+				}
+			})
+		})
+	}
+}
+
 func benchmarkXlog(l log.Logger) {
 	l.Trace(log.NoContext, "trace")
 	l.Debug(log.NoContext, "debug")
@@ -34,9 +34,9 @@ func Capture(skipCount int) (*Caller, func(), error) {
 		return nil, nil, ErrNoFrames
 	}
 
-	f, _ := runtime.CallersFrames(caller.pcs[:1]).Next()
-	if f.PC == 0 {
-		return nil, nil, ErrNoFrames
+	f, err := frameFor(caller.pcs[0])
+	if err != nil {
+		return nil, nil, err
 	}
 
 	caller.File, caller.Line = f.File, f.Line
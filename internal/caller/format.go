@@ -0,0 +1,176 @@
+package caller
+
+import (
+	"cmp"
+	"path"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/anticrew/go-x/xio"
+)
+
+// Format
+// Формат строкового представления источника вызова
+type Format uint
+
+const (
+	// FormatShort
+	// Файл, сокращенный до названия пакета, строка и функция:
+	// "caller/take.go:12 github.com/anticrew/log/internal/caller.Take"
+	FormatShort Format = iota
+
+	// FormatFull
+	// Полный путь к файлу, строка и функция:
+	// "/src/log/internal/caller/take.go:12 github.com/anticrew/log/internal/caller.Take"
+	FormatFull
+
+	// FormatFileLine
+	// Файл, сокращенный до названия пакета, и строка: "caller/take.go:12"
+	FormatFileLine
+
+	// FormatFunction
+	// Только функция: "github.com/anticrew/log/internal/caller.Take"
+	FormatFunction
+
+	// FormatModule
+	// Путь к файлу относительно корня модуля, строка и функция:
+	// "internal/caller/take.go:12 github.com/anticrew/log/internal/caller.Take"
+	FormatModule
+)
+
+// _formatCount
+// Кол-во строковых форматов источника вызова
+const _formatCount = FormatModule + 1
+
+// Append
+// Добавляет строковое представление источника вызова в указанном формате в buf
+func (c *Caller) Append(buf *xio.Buffer, format Format) {
+	appendFormat(buf, format, c.File, c.Line, c.Function)
+}
+
+// String
+// Возвращает строковое представление источника вызова в указанном формате
+func (c *Caller) String(format Format) string {
+	buf := xio.NewBuffer()
+	defer buf.Dispose()
+
+	c.Append(buf, format)
+
+	return buf.String()
+}
+
+func appendFormat(buf *xio.Buffer, format Format, file string, line int, function string) {
+	switch format {
+	case FormatFull:
+		appendFileLine(buf, file, line)
+		buf.WriteByte(' ').WriteString(function)
+
+	case FormatFileLine:
+		appendFileLine(buf, ShortPath(file), line)
+
+	case FormatFunction:
+		buf.WriteString(function)
+
+	case FormatModule:
+		appendFileLine(buf, ModulePath(file, function), line)
+		buf.WriteByte(' ').WriteString(function)
+
+	case FormatShort:
+		fallthrough
+
+	default:
+		appendFileLine(buf, ShortPath(file), line)
+		buf.WriteByte(' ').WriteString(function)
+	}
+}
+
+func appendFileLine(buf *xio.Buffer, file string, line int) {
+	buf.WriteString(file).
+		WriteByte(':').
+		WriteInt64(int64(line))
+}
+
+// ShortPath
+// Сокращает путь к файлу до названия пакета и файла: "/src/log/internal/caller/take.go" -> "caller/take.go".
+// Если путь содержит менее двух разделителей, возвращается без изменений
+func ShortPath(file string) string {
+	// Find the last separator.
+	idx := strings.LastIndexByte(file, '/')
+	if idx == -1 {
+		return file
+	}
+
+	// Find the penultimate separator.
+	idx = strings.LastIndexByte(file[:idx], '/')
+	if idx == -1 {
+		return file
+	}
+
+	return file[idx+1:]
+}
+
+// ModulePath
+// Возвращает путь к файлу относительно корня модуля, которому принадлежит пакет функции. Модули определяются по
+// информации о сборке (runtime/debug.ReadBuildInfo). Для пакетов вне известных модулей, например стандартной
+// библиотеки, возвращается путь пакета и название файла: "testing/testing.go"
+func ModulePath(file, function string) string {
+	pkg := PackagePath(function)
+	if len(pkg) == 0 {
+		return ShortPath(file)
+	}
+
+	name := path.Base(file)
+
+	for _, module := range loadModules() {
+		if pkg == module {
+			return name
+		}
+
+		if rel, ok := strings.CutPrefix(pkg, module); ok && rel[0] == '/' {
+			return rel[1:] + "/" + name
+		}
+	}
+
+	return pkg + "/" + name
+}
+
+// PackagePath
+// Возвращает путь пакета из полного названия функции:
+// "github.com/anticrew/log/internal/caller.Take.func1" -> "github.com/anticrew/log/internal/caller"
+func PackagePath(function string) string {
+	start := strings.LastIndexByte(function, '/') + 1
+
+	idx := strings.IndexByte(function[start:], '.')
+	if idx == -1 {
+		return ""
+	}
+
+	return function[:start+idx]
+}
+
+// loadModules
+// Возвращает пути модулей из информации о сборке, отсортированные по убыванию длины, чтобы вложенные модули
+// проверялись раньше родительских
+var loadModules = sync.OnceValue(func() []string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return nil
+	}
+
+	modules := make([]string, 0, len(info.Deps)+1)
+	if len(info.Main.Path) > 0 {
+		modules = append(modules, info.Main.Path)
+	}
+
+	for _, dep := range info.Deps {
+		modules = append(modules, dep.Path)
+	}
+
+	slices.SortFunc(modules, func(a, b string) int {
+		return cmp.Compare(len(b), len(a))
+	})
+
+	return modules
+})
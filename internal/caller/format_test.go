@@ -0,0 +1,130 @@
+package caller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Caller_String(t *testing.T) {
+	t.Parallel()
+
+	c := &Caller{
+		File:     "/src/log/internal/caller/take.go",
+		Line:     12,
+		Function: "github.com/anticrew/log/internal/caller.Take",
+	}
+
+	testCases := map[string]struct {
+		format   Format
+		expected string
+	}{
+		"short": {
+			format:   FormatShort,
+			expected: "caller/take.go:12 github.com/anticrew/log/internal/caller.Take",
+		},
+		"full": {
+			format:   FormatFull,
+			expected: "/src/log/internal/caller/take.go:12 github.com/anticrew/log/internal/caller.Take",
+		},
+		"file-line": {
+			format:   FormatFileLine,
+			expected: "caller/take.go:12",
+		},
+		"function": {
+			format:   FormatFunction,
+			expected: "github.com/anticrew/log/internal/caller.Take",
+		},
+		"module": {
+			format:   FormatModule,
+			expected: "internal/caller/take.go:12 github.com/anticrew/log/internal/caller.Take",
+		},
+		"unknown": {
+			format:   Format(100),
+			expected: "caller/take.go:12 github.com/anticrew/log/internal/caller.Take",
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, c.String(test.format))
+		})
+	}
+}
+
+func Test_ShortPath(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "caller/take.go", ShortPath("/src/log/internal/caller/take.go"))
+	assert.Equal(t, "/take.go", ShortPath("/take.go"))
+	assert.Equal(t, "take.go", ShortPath("take.go"))
+}
+
+func Test_PackagePath(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]string{
+		"github.com/anticrew/log/internal/caller.Take":              "github.com/anticrew/log/internal/caller",
+		"github.com/anticrew/log/internal/caller.Test_Take.func1.1": "github.com/anticrew/log/internal/caller",
+		"github.com/anticrew/log.(*logger).Info":                    "github.com/anticrew/log",
+		"github.com/anticrew/log.Map[go.shape.string].Func":         "github.com/anticrew/log",
+		"testing.tRunner": "testing",
+		"main.main":       "main",
+		"nodot":           "",
+	}
+
+	for function, expected := range testCases {
+		assert.Equal(t, expected, PackagePath(function), function)
+	}
+}
+
+func Test_ModulePath(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		file     string
+		function string
+		expected string
+	}{
+		"main-module-package": {
+			file:     "/src/log/internal/caller/take.go",
+			function: "github.com/anticrew/log/internal/caller.Take",
+			expected: "internal/caller/take.go",
+		},
+		"main-module-root": {
+			file:     "/src/log/log.go",
+			function: "github.com/anticrew/log.Info",
+			expected: "log.go",
+		},
+		"dependency": {
+			file:     "/go/pkg/mod/github.com/stretchr/testify@v1.10.0/assert/assertions.go",
+			function: "github.com/stretchr/testify/assert.didPanic",
+			expected: "assert/assertions.go",
+		},
+		"std": {
+			file:     "/usr/local/go/src/testing/testing.go",
+			function: "testing.tRunner",
+			expected: "testing/testing.go",
+		},
+		"prefix-is-not-module": {
+			file:     "/src/logger/logger.go",
+			function: "github.com/anticrew/logger.New",
+			expected: "github.com/anticrew/logger/logger.go",
+		},
+		"no-package": {
+			file:     "/src/log/internal/caller/take.go",
+			function: "",
+			expected: "caller/take.go",
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, ModulePath(test.file, test.function))
+		})
+	}
+}
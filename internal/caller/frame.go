@@ -0,0 +1,109 @@
+package caller
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/anticrew/go-x/xio"
+)
+
+// _frames
+// Кеш информации об источниках вызова по адресу вызова (PC). Кол-во мест вызова записи логов в программе конечно,
+// поэтому кеш не ограничивается по размеру
+var _frames sync.Map // map[uintptr]*Frame
+
+// _uncached
+// Флаг разрешения адреса вызова без кеша _frames, устанавливается DisableCache
+var _uncached atomic.Bool
+
+// DisableCache
+// Отключает кеш информации об источниках вызова до вызова возвращенной функции: адрес вызова разрешается с помощью
+// runtime.CallersFrames при каждом вызове Lookup. Используется в бенчмарках для сравнения с кешированием
+func DisableCache() (restore func()) {
+	_uncached.Store(true)
+
+	return func() {
+		_uncached.Store(false)
+	}
+}
+
+// Frame
+// Неизменяемая информация об источнике вызова, общая для всех вызовов из одного места в коде. Строковые
+// представления вычисляются при первом обращении и кешируются
+type Frame struct {
+	File     string
+	Line     int
+	Function string
+
+	formatted [_formatCount]atomic.Pointer[string]
+}
+
+// Lookup
+// Возвращает информацию об источнике вызова, пропуская 2 вызова: вызов самого Lookup и runtime.Callers.
+// Разрешение адреса вызова с помощью runtime.CallersFrames выполняется один раз для каждого места вызова
+func Lookup(skipCount int) (*Frame, error) {
+	// always skip runtime.Callers and caller.Lookup
+	skipCount += 2
+
+	var pcs [1]uintptr
+	if framesCount := runtime.Callers(skipCount, pcs[:]); framesCount < 1 {
+		return nil, ErrNoFrames
+	}
+
+	return frameFor(pcs[0])
+}
+
+// frameFor
+// Возвращает информацию об источнике вызова по адресу из кеша или разрешает адрес и сохраняет результат в кеш
+func frameFor(pc uintptr) (*Frame, error) {
+	uncached := _uncached.Load()
+
+	if !uncached {
+		if f, ok := _frames.Load(pc); ok {
+			return f.(*Frame), nil //nolint:forcetypeassert // _frames stores only *Frame
+		}
+	}
+
+	rf, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	if rf.PC == 0 {
+		return nil, ErrNoFrames
+	}
+
+	frame := &Frame{
+		File:     rf.File,
+		Line:     rf.Line,
+		Function: rf.Function,
+	}
+
+	if uncached {
+		return frame, nil
+	}
+
+	f, _ := _frames.LoadOrStore(pc, frame)
+
+	return f.(*Frame), nil //nolint:forcetypeassert // _frames stores only *Frame
+}
+
+// String
+// Возвращает строковое представление источника вызова в указанном формате. Для неизвестного формата используется
+// FormatShort
+func (f *Frame) String(format Format) string {
+	if format >= _formatCount {
+		format = FormatShort
+	}
+
+	if s := f.formatted[format].Load(); s != nil {
+		return *s
+	}
+
+	buf := xio.NewBuffer()
+	defer buf.Dispose()
+
+	appendFormat(buf, format, f.File, f.Line, f.Function)
+
+	s := buf.String()
+	f.formatted[format].Store(&s)
+
+	return s
+}
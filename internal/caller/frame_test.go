@@ -0,0 +1,86 @@
+package caller
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/anticrew/go-x/xio"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func lookupTwice() (*Frame, *Frame) {
+	var frames [2]*Frame
+
+	for i := range frames {
+		f, err := Lookup(0)
+		if err != nil {
+			panic(err)
+		}
+
+		frames[i] = f
+	}
+
+	return frames[0], frames[1]
+}
+
+func Test_Lookup(t *testing.T) {
+	t.Parallel()
+
+	first, second := lookupTwice()
+	assert.Same(t, first, second)
+	assert.Equal(t, "github.com/anticrew/log/internal/caller.lookupTwice", first.Function)
+	assert.Equal(t, 16, first.Line) // where is Lookup called
+
+	other, err := Lookup(0)
+	require.NoError(t, err)
+	assert.NotSame(t, first, other)
+
+	assert.Equal(t, "caller/frame_test.go:16", first.String(FormatFileLine))
+	assert.Same(t, first.formatted[FormatFileLine].Load(), first.formatted[FormatFileLine].Load())
+	assert.Equal(t, first.String(FormatShort), first.String(Format(100)))
+
+	_, err = Lookup(MaxFrames)
+	require.ErrorIs(t, err, ErrNoFrames)
+}
+
+func Benchmark_TakeFormat(b *testing.B) {
+	b.Run("cached", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for b.Loop() {
+			s, err := TakeFormat(0, FormatShort)
+			_ = s
+			_ = err
+		}
+	})
+
+	b.Run("uncached", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for b.Loop() {
+			var pcs [1]uintptr
+			runtime.Callers(1, pcs[:])
+			f, _ := runtime.CallersFrames(pcs[:]).Next()
+
+			buf := xio.NewBuffer()
+			appendFormat(buf, FormatShort, f.File, f.Line, f.Function)
+			_ = buf.String()
+			buf.Dispose()
+		}
+	})
+}
+
+//nolint:paralleltest // disables the shared cache
+func Test_DisableCache(t *testing.T) {
+	restore := DisableCache()
+
+	first, second := lookupTwice()
+	assert.NotSame(t, first, second)
+	assert.Equal(t, first.Function, second.Function)
+
+	restore()
+
+	first, second = lookupTwice()
+	assert.Same(t, first, second)
+}
@@ -0,0 +1,49 @@
+package caller
+
+import (
+	"runtime"
+
+	"github.com/anticrew/go-x/xio"
+)
+
+// _stackDepth
+// Наибольшее кол-во вызовов в стеке, возвращаемом Stack
+const _stackDepth = 64
+
+// Stack
+// Возвращает стек вызовов текущей горутины, пропуская 2 вызова: вызов самого Stack и runtime.Callers. Каждый вызов
+// записывается в две строки, как в runtime/debug.Stack: название функции и полный путь к файлу с номером строки
+func Stack(skipCount int) string {
+	// always skip runtime.Callers and caller.Stack
+	skipCount += 2
+
+	var pcs [_stackDepth]uintptr
+	n := runtime.Callers(skipCount, pcs[:])
+	if n == 0 {
+		return ""
+	}
+
+	buf := xio.NewBuffer()
+	defer buf.Dispose()
+
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		f, more := frames.Next()
+
+		if buf.Len() > 0 {
+			buf.WriteByte('\n')
+		}
+
+		buf.WriteString(f.Function)
+		buf.WriteString("\n\t")
+		buf.WriteString(f.File)
+		buf.WriteByte(':')
+		buf.WriteInt64(int64(f.Line))
+
+		if !more {
+			break
+		}
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,27 @@
+package caller
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func stackInside() string {
+	return Stack(0)
+}
+
+func Test_Stack(t *testing.T) {
+	t.Parallel()
+
+	lines := strings.Split(stackInside(), "\n")
+	require.GreaterOrEqual(t, len(lines), 4)
+
+	assert.Equal(t, "github.com/anticrew/log/internal/caller.stackInside", lines[0])
+	assert.True(t, strings.HasPrefix(lines[1], "\t"))
+	assert.True(t, strings.HasSuffix(lines[1], "caller/stack_test.go:12"), lines[1])
+	assert.Equal(t, "github.com/anticrew/log/internal/caller.Test_Stack", lines[2])
+
+	assert.Empty(t, Stack(MaxFrames))
+}
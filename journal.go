@@ -0,0 +1,96 @@
+package log
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/anticrew/go-x/xio"
+)
+
+// journalEncoder
+// Общий для драйверов энкодер формата FormatJournal: нативный протокол journald, каждое поле записывается как
+// KEY=value с переводом строки, значения с переводом строки - как KEY, перевод строки, длина (uint64 little-endian),
+// значение и перевод строки
+type journalEncoder struct {
+	identifier string
+	facility   string
+	sourceKey  string
+}
+
+func newJournalEncoder(opt Options) *journalEncoder {
+	identifier := opt.SyslogAppName
+	if len(identifier) == 0 {
+		identifier = filepath.Base(os.Args[0])
+	}
+
+	buf := xio.NewBuffer()
+	defer buf.Dispose()
+
+	return &journalEncoder{
+		identifier: identifier,
+		facility:   buf.WriteInt64(int64(opt.SyslogFacility)).String(),
+		sourceKey:  opt.SourceKey,
+	}
+}
+
+func (e *journalEncoder) encode(buf *xio.Buffer, level Level, _ time.Time, msg string, params []param) {
+	writeJournalField(buf, "MESSAGE", msg)
+	buf.WriteString("PRIORITY=").WriteInt64(int64(LevelSeverity(level))).WriteByte('\n')
+	writeJournalField(buf, "SYSLOG_FACILITY", e.facility)
+	writeJournalField(buf, "SYSLOG_IDENTIFIER", e.identifier)
+
+	for _, p := range params {
+		if p.key == e.sourceKey {
+			if source, ok := p.value.(map[string]any); ok {
+				writeJournalField(buf, "CODE_FILE", syslogValue(source[SourceFileKey]))
+				writeJournalField(buf, "CODE_LINE", syslogValue(source[SourceLineKey]))
+				writeJournalField(buf, "CODE_FUNC", syslogValue(source[SourceFunctionKey]))
+
+				continue
+			}
+		}
+
+		writeJournalField(buf, journalFieldName(p.key), syslogValue(p.value))
+	}
+}
+
+// writeJournalField
+// Записывает поле журнала в нативном протоколе journald
+func writeJournalField(buf *xio.Buffer, name, value string) {
+	buf.WriteString(name)
+
+	if !strings.Contains(value, "\n") {
+		buf.WriteByte('=').WriteString(value).WriteByte('\n')
+		return
+	}
+
+	buf.WriteByte('\n')
+	_, _ = buf.Write(binary.LittleEndian.AppendUint64(nil, uint64(len(value))))
+	buf.WriteString(value).WriteByte('\n')
+}
+
+// journalFieldName
+// Возвращает допустимое название поля журнала: до 64 символов A-Z, 0-9 и '_', начинается с буквы. Недопустимые
+// символы заменяются на '_', ведущие '_' (поля journald) удаляются, к названию, начинающемуся с цифры, добавляется
+// префикс ARG_
+func journalFieldName(key string) string {
+	name := strings.TrimLeft(strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		default:
+			return '_'
+		}
+	}, key), "_")
+
+	if len(name) == 0 || (name[0] >= '0' && name[0] <= '9') {
+		name = "ARG_" + name
+	}
+
+	return name[:min(len(name), 64)]
+}
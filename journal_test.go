@@ -0,0 +1,46 @@
+package log
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FormatJournal(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	l := NewLogger(WithFormat(FormatJournal), WithWriter(buf), WithSyslog(FacilityDaemon, "app"),
+		WithSource("source"))
+
+	l.Named("svc").Error(NoContext, errors.New("failed"), "multi\nline", String("user.id", "u1"), Int("1st", 1))
+
+	out := buf.String()
+
+	msg := "multi\nline"
+	size := binary.LittleEndian.AppendUint64(nil, uint64(len(msg)))
+	assert.Contains(t, out, "MESSAGE\n"+string(size)+msg+"\n")
+	assert.Contains(t, out, "PRIORITY=3\nSYSLOG_FACILITY=3\nSYSLOG_IDENTIFIER=app\n")
+	assert.Contains(t, out, "USER_ID=u1\n")
+	assert.Contains(t, out, "ARG_1ST=1\n")
+	assert.Contains(t, out, "ERROR=failed\n")
+	assert.Contains(t, out, "LOGGER=svc\n")
+	assert.Contains(t, out, "CODE_FILE=")
+	assert.Contains(t, out, "CODE_LINE=20\n")
+	assert.Contains(t, out, "CODE_FUNC=github.com/anticrew/log.Test_FormatJournal\n")
+	require.NotContains(t, out, "SOURCE")
+}
+
+func Test_journalFieldName(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "USER_ID", journalFieldName("user.id"))
+	assert.Equal(t, "HIDDEN", journalFieldName("__hidden"))
+	assert.Equal(t, "ARG_1ST", journalFieldName("1st"))
+	assert.Equal(t, "ARG_", journalFieldName("_"))
+	assert.Len(t, journalFieldName(string(bytes.Repeat([]byte("a"), 100))), 64)
+}
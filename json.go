@@ -0,0 +1,142 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/anticrew/go-x/xio"
+)
+
+// writeJSONString
+// Записывает строку JSON в кавычках, экранируя '"', '\' и управляющие символы. Некорректные последовательности UTF-8
+// заменяются на U+FFFD
+func writeJSONString(buf *xio.Buffer, s string) {
+	const hex = "0123456789abcdef"
+
+	if !utf8.ValidString(s) {
+		s = strings.ToValidUTF8(s, "�")
+	}
+
+	buf.WriteByte('"')
+
+	for i := range len(s) {
+		switch c := s[i]; {
+		case c == '"' || c == '\\':
+			buf.WriteByte('\\').WriteByte(c)
+		case c == '\n':
+			buf.WriteString(`\n`)
+		case c == '\r':
+			buf.WriteString(`\r`)
+		case c == '\t':
+			buf.WriteString(`\t`)
+		case c < ' ':
+			buf.WriteString(`\u00`).WriteByte(hex[c>>4]).WriteByte(hex[c&0xf])
+		default:
+			buf.WriteByte(c)
+		}
+	}
+
+	buf.WriteByte('"')
+}
+
+// writeJSONNumber
+// Записывает число, если v является целым числом или числом с плавающей точкой, и сообщает о записи. NaN и
+// бесконечности не поддерживаются JSON и записываются строкой
+func writeJSONNumber(buf *xio.Buffer, v any) bool {
+	switch v := v.(type) {
+	case int:
+		buf.WriteInt64(int64(v))
+	case int8:
+		buf.WriteInt64(int64(v))
+	case int16:
+		buf.WriteInt64(int64(v))
+	case int32:
+		buf.WriteInt64(int64(v))
+	case int64:
+		buf.WriteInt64(v)
+	case uint:
+		buf.WriteUint64(uint64(v))
+	case uint8:
+		buf.WriteUint64(uint64(v))
+	case uint16:
+		buf.WriteUint64(uint64(v))
+	case uint32:
+		buf.WriteUint64(uint64(v))
+	case uint64:
+		buf.WriteUint64(v)
+	case float32:
+		writeJSONFloat(buf, float64(v), 32)
+	case float64:
+		writeJSONFloat(buf, v, 64)
+	default:
+		return false
+	}
+
+	return true
+}
+
+// writeJSONFloat
+// Записывает число с плавающей точкой, целые значения записываются с дробной частью, чтобы отличаться от целых
+// чисел. NaN и бесконечности записываются строкой
+func writeJSONFloat(buf *xio.Buffer, f float64, bitSize int) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		writeJSONString(buf, syslogValue(f))
+		return
+	}
+
+	var tmp [32]byte
+
+	b := strconv.AppendFloat(tmp[:0], f, 'f', -1, bitSize)
+	if !bytes.ContainsAny(b, ".eE") {
+		b = append(b, ".0"...)
+	}
+
+	buf.WriteBytes(b)
+}
+
+// writeJSONValue
+// Записывает значение аргумента в JSON: числа и логические значения записываются как есть, составные значения -
+// объектами и массивами JSON, остальные значения - строками
+func writeJSONValue(buf *xio.Buffer, v any) {
+	if writeJSONNumber(buf, v) {
+		return
+	}
+
+	switch v := v.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteBool(v)
+	case string:
+		writeJSONString(buf, v)
+	case time.Time, time.Duration, error, fmt.Stringer, []byte:
+		writeJSONString(buf, syslogValue(v))
+	default:
+		if b, err := json.Marshal(v); err == nil {
+			_, _ = buf.Write(b)
+			return
+		}
+
+		writeJSONString(buf, syslogValue(v))
+	}
+}
+
+// writeJSONFields
+// Записывает пары ключ-значение объекта JSON через запятую
+func writeJSONFields(buf *xio.Buffer, kv ...any) {
+	for i := 0; i+1 < len(kv); i += 2 {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		writeJSONString(buf, kv[i].(string)) //nolint:forcetypeassert // keys are strings
+		buf.WriteByte(':')
+		writeJSONValue(buf, kv[i+1])
+	}
+}
@@ -0,0 +1,220 @@
+package log
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Level
+// Уровень важности лога. Значения не зависят от выбранного драйвера: между стандартными уровнями оставлен шаг в 4,
+// как в log/slog, каждый драйвер самостоятельно преобразует Level в собственный тип
+type Level int
+
+const (
+	// LevelTrace
+	// Уровень для максимально подробных отладочных логов
+	LevelTrace Level = -8
+
+	// LevelDebug
+	// Уровень для отладочных логов
+	LevelDebug Level = -4
+
+	// LevelInfo
+	// Уровень для информационных логов
+	LevelInfo Level = 0
+
+	// LevelWarn
+	// Уровень для предупреждений
+	LevelWarn Level = 4
+
+	// LevelError
+	// Уровень для ошибок
+	LevelError Level = 8
+)
+
+const (
+	_traceValue = "TRACE"
+	_debugValue = "DEBUG"
+	_infoValue  = "INFO"
+	_warnValue  = "WARN"
+	_errorValue = "ERROR"
+)
+
+// levelRegistry
+// Неизменяемый набор зарегистрированных уровней и их наименований. При регистрации нового уровня создается копия
+type levelRegistry struct {
+	// levels
+	// Зарегистрированные уровни в порядке возрастания
+	levels []Level
+
+	// names
+	// Наименования зарегистрированных уровней
+	names map[Level]string
+}
+
+var (
+	// _defaultLevels
+	// Набор стандартных уровней
+	_defaultLevels = &levelRegistry{
+		levels: []Level{LevelTrace, LevelDebug, LevelInfo, LevelWarn, LevelError},
+		names: map[Level]string{
+			LevelTrace: _traceValue,
+			LevelDebug: _debugValue,
+			LevelInfo:  _infoValue,
+			LevelWarn:  _warnValue,
+			LevelError: _errorValue,
+		},
+	}
+
+	// _levels
+	// Текущий набор уровней, nil соответствует _defaultLevels
+	_levels atomic.Pointer[levelRegistry]
+
+	// _levelsMu
+	// Мьютекс, последовательно выполняющий регистрацию уровней
+	_levelsMu sync.Mutex
+)
+
+// loadLevels
+// Возвращает текущий набор уровней
+func loadLevels() *levelRegistry {
+	if r := _levels.Load(); r != nil {
+		return r
+	}
+
+	return _defaultLevels
+}
+
+// RegisterLevel
+// Регистрирует уровень с указанным наименованием или переименовывает уже зарегистрированный уровень. Наименование
+// используется в Level.String, ParseLevel и всех драйверах. Наименование не должно быть пустым, содержать пробелы и
+// символы '+' и '-', а также совпадать без учета регистра с наименованием другого уровня.
+// Драйвер zap поддерживает значения уровней только в диапазоне int8
+func RegisterLevel(value Level, name string) error {
+	if len(name) == 0 || strings.ContainsAny(name, "+- \t\r\n") {
+		return fmt.Errorf("%w: name %q", ErrInvalidLevel, name)
+	}
+
+	_levelsMu.Lock()
+	defer _levelsMu.Unlock()
+
+	current := loadLevels()
+	for level, levelName := range current.names {
+		if level != value && strings.EqualFold(levelName, name) {
+			return fmt.Errorf("%w: name %q is already used by %d", ErrInvalidLevel, name, level)
+		}
+	}
+
+	next := &levelRegistry{
+		levels: make([]Level, 0, len(current.levels)+1),
+		names:  make(map[Level]string, len(current.names)+1),
+	}
+
+	for _, level := range current.levels {
+		if level != value {
+			next.levels = append(next.levels, level)
+		}
+
+		next.names[level] = current.names[level]
+	}
+
+	next.levels = append(next.levels, value)
+	slices.Sort(next.levels)
+	next.names[value] = name
+
+	_levels.Store(next)
+	return nil
+}
+
+// String
+// Возвращает наименование уровня. Для незарегистрированных уровней возвращает смещение относительно ближайшего
+// меньшего зарегистрированного уровня в формате "INFO+2", а для уровней ниже наименьшего - в формате "TRACE-2"
+func (l Level) String() string {
+	return loadLevels().format(l, nil)
+}
+
+// formatLevel
+// Возвращает наименование уровня с учетом наименований, переопределенных для конкретного Logger
+func formatLevel(l Level, names map[Level]string) string {
+	return loadLevels().format(l, names)
+}
+
+// format
+// Возвращает наименование уровня, в первую очередь используя names, а затем зарегистрированные наименования
+func (r *levelRegistry) format(l Level, names map[Level]string) string {
+	if name, ok := names[l]; ok {
+		return name
+	}
+
+	if name, ok := r.names[l]; ok {
+		return name
+	}
+
+	base := r.levels[0]
+	for _, level := range r.levels {
+		if level > l {
+			break
+		}
+
+		base = level
+	}
+
+	name, ok := names[base]
+	if !ok {
+		name = r.names[base]
+	}
+
+	return fmt.Sprintf("%s%+d", name, l-base)
+}
+
+// MarshalText
+// Реализует encoding.TextMarshaler, результат совпадает с Level.String
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText
+// Реализует encoding.TextUnmarshaler, разбор выполняется с помощью ParseLevel
+func (l *Level) UnmarshalText(text []byte) error {
+	level, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+
+	*l = level
+	return nil
+}
+
+// Set
+// Реализует flag.Value, разбор выполняется с помощью ParseLevel
+func (l *Level) Set(s string) error {
+	return l.UnmarshalText([]byte(s))
+}
+
+// ParseLevel
+// Разбирает наименование зарегистрированного уровня без учета регистра: "TRACE", "debug", "Info" и т.д. Также
+// поддерживается формат со смещением, возвращаемый Level.String: "INFO+2", "ERROR-1"
+func ParseLevel(s string) (Level, error) {
+	name, offset := s, 0
+
+	if idx := strings.IndexAny(s, "+-"); idx > 0 {
+		n, err := strconv.Atoi(s[idx:])
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidLevel, s)
+		}
+
+		name, offset = s[:idx], n
+	}
+
+	for level, levelName := range loadLevels().names {
+		if strings.EqualFold(levelName, name) {
+			return level + Level(offset), nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: %q", ErrInvalidLevel, s)
+}
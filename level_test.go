@@ -0,0 +1,259 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseLevel(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		value    string
+		expected Level
+		err      error
+	}
+
+	testCases := map[string]testCase{
+		"trace": {
+			value:    "TRACE",
+			expected: LevelTrace,
+		},
+		"debug-lower": {
+			value:    "debug",
+			expected: LevelDebug,
+		},
+		"info-mixed": {
+			value:    "Info",
+			expected: LevelInfo,
+		},
+		"warn": {
+			value:    "WARN",
+			expected: LevelWarn,
+		},
+		"error": {
+			value:    "error",
+			expected: LevelError,
+		},
+		"offset-plus": {
+			value:    "INFO+2",
+			expected: LevelInfo + 2,
+		},
+		"offset-minus": {
+			value:    "trace-1",
+			expected: LevelTrace - 1,
+		},
+		"err-unknown": {
+			value: "notice",
+			err:   ErrInvalidLevel,
+		},
+		"err-empty": {
+			value: "",
+			err:   ErrInvalidLevel,
+		},
+		"err-offset": {
+			value: "INFO+x",
+			err:   ErrInvalidLevel,
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := ParseLevel(test.value)
+			if test.err != nil {
+				require.ErrorIs(t, err, test.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func Test_Level_String(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[Level]string{
+		LevelTrace:     "TRACE",
+		LevelDebug:     "DEBUG",
+		LevelInfo:      "INFO",
+		LevelWarn:      "WARN",
+		LevelError:     "ERROR",
+		LevelInfo + 2:  "INFO+2",
+		LevelError + 4: "ERROR+4",
+		LevelTrace - 2: "TRACE-2",
+	}
+
+	for level, expected := range testCases {
+		t.Run(expected, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, expected, level.String())
+
+			parsed, err := ParseLevel(level.String())
+			require.NoError(t, err)
+			assert.Equal(t, level, parsed)
+		})
+	}
+}
+
+func Test_Level_Text(t *testing.T) {
+	t.Parallel()
+
+	type config struct {
+		Level  Level  `json:"level"`
+		Format Format `json:"format"`
+	}
+
+	var c config
+	require.NoError(t, json.Unmarshal([]byte(`{"level":"warn","format":"LogFmt"}`), &c))
+	assert.Equal(t, config{Level: LevelWarn, Format: FormatLogFmt}, c)
+
+	data, err := json.Marshal(c)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"level":"WARN","format":"logfmt"}`, string(data))
+
+	require.ErrorIs(t, json.Unmarshal([]byte(`{"level":"verbose"}`), &c), ErrInvalidLevel)
+	require.ErrorIs(t, json.Unmarshal([]byte(`{"format":"xml"}`), &c), ErrInvalidFormat)
+
+	_, err = json.Marshal(config{Format: Format(42)})
+	require.ErrorIs(t, err, ErrInvalidFormat)
+}
+
+func Test_Level_Flag(t *testing.T) {
+	t.Parallel()
+
+	var (
+		level  = LevelInfo
+		format = FormatText
+	)
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Var(&level, "level", "")
+	fs.Var(&format, "format", "")
+
+	require.NoError(t, fs.Parse([]string{"-level", "debug", "-format", "JSON"}))
+	assert.Equal(t, LevelDebug, level)
+	assert.Equal(t, FormatJSON, format)
+
+	require.Error(t, fs.Parse([]string{"-level", "loud"}))
+}
+
+func Test_Level_Minimum(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	l := NewLogger(WithFormat(FormatJSON), WithWriter(buf), WithLevel("", LevelInfo))
+
+	l.Trace(NoContext, "trace")
+	l.Debug(NoContext, "debug")
+	l.Info(NoContext, "info")
+	l.Warn(NoContext, nil, "warn")
+	l.Error(NoContext, nil, "error")
+
+	entries := decodeEntries(t, buf)
+	require.Len(t, entries, 3)
+	assert.Equal(t, "info", entries[0][MessageKey])
+	assert.Equal(t, "warn", entries[1][MessageKey])
+	assert.Equal(t, "error", entries[2][MessageKey])
+}
+
+func Test_ParseFormat(t *testing.T) {
+	t.Parallel()
+
+	for f := FormatText; f.IsValid(); f++ {
+		actual, err := ParseFormat(f.String())
+		require.NoError(t, err)
+		assert.Equal(t, f, actual)
+	}
+
+	_, err := ParseFormat("yaml")
+	require.ErrorIs(t, err, ErrInvalidFormat)
+}
+
+// restoreLevels
+// Восстанавливает набор уровней после завершения теста. Тесты, изменяющие набор уровней, не должны быть параллельными
+func restoreLevels(t *testing.T) {
+	t.Helper()
+
+	current := _levels.Load()
+	t.Cleanup(func() {
+		_levels.Store(current)
+	})
+}
+
+//nolint:paralleltest // modifies global level registry
+func Test_RegisterLevel(t *testing.T) {
+	restoreLevels(t)
+
+	const (
+		levelNotice = LevelInfo + 2
+		levelAudit  = LevelError + 4
+	)
+
+	require.NoError(t, RegisterLevel(levelNotice, "NOTICE"))
+	require.NoError(t, RegisterLevel(levelAudit, "AUDIT"))
+	require.NoError(t, RegisterLevel(LevelWarn, "WARNING"))
+
+	assert.Equal(t, "NOTICE", levelNotice.String())
+	assert.Equal(t, "NOTICE+1", (levelNotice + 1).String())
+	assert.Equal(t, "WARNING", LevelWarn.String())
+	assert.Equal(t, "AUDIT+1", (levelAudit + 1).String())
+
+	level, err := ParseLevel("notice")
+	require.NoError(t, err)
+	assert.Equal(t, levelNotice, level)
+
+	level, err = ParseLevel("Warning")
+	require.NoError(t, err)
+	assert.Equal(t, LevelWarn, level)
+
+	_, err = ParseLevel("WARN")
+	require.ErrorIs(t, err, ErrInvalidLevel)
+
+	require.ErrorIs(t, RegisterLevel(LevelInfo+1, "notice"), ErrInvalidLevel)
+	require.ErrorIs(t, RegisterLevel(LevelInfo+1, ""), ErrInvalidLevel)
+	require.ErrorIs(t, RegisterLevel(LevelInfo+1, "X-RAY"), ErrInvalidLevel)
+
+	buf := &bytes.Buffer{}
+	l := NewLogger(WithFormat(FormatJSON), WithWriter(buf), WithLevel("", LevelTrace))
+	l.Write(NoContext, levelNotice, "notice")
+
+	entries := decodeEntries(t, buf)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "NOTICE", entries[0][LevelKey])
+}
+
+func Test_WithLevelNames(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	l := NewLogger(WithFormat(FormatJSON), WithWriter(buf), WithLevel("", LevelTrace), WithLevelNames(map[Level]string{
+		LevelInfo:  "info",
+		LevelError: "err",
+	}))
+
+	l.Info(NoContext, "info")
+	l.Error(NoContext, nil, "error")
+	l.Write(NoContext, LevelInfo+1, "info+1")
+	l.Warn(NoContext, nil, "warn")
+
+	expected := []string{"info", "err", "info+1", "WARN"}
+
+	entries := decodeEntries(t, buf)
+	require.Len(t, entries, len(expected))
+
+	for i, level := range expected {
+		assert.Equal(t, level, entries[i][LevelKey])
+	}
+}
@@ -2,9 +2,13 @@ package log
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"os"
+	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -28,14 +32,10 @@ const (
 	// ErrorKey
 	// Ключ по умолчанию для записи текста ошибки
 	ErrorKey = "error"
-)
 
-const (
-	_traceValue = "TRACE"
-	_debugValue = "DEBUG"
-	_infoValue  = "INFO"
-	_warnValue  = "WARN"
-	_errorValue = "ERROR"
+	// NameKey
+	// Ключ по умолчанию для записи имени Logger
+	NameKey = "logger"
 )
 
 // Trace
@@ -80,18 +80,37 @@ func Write(ctx context.Context, level Level, msg string, args ...Arg) {
 	defaultLoggerFor(ctx).Write(ctx, level, msg, args...)
 }
 
-var _defaultLogger = NewLogger()
+// Enabled
+// Сообщает, будет ли записан лог с указанным уровнем. Позволяет не вычислять дорогостоящие аргументы для отключенных
+// уровней
+func Enabled(ctx context.Context, level Level) bool {
+	return defaultLoggerFor(ctx).Enabled(ctx, level)
+}
+
+// defaultLogger
+// Обертка для атомарного хранения Logger по умолчанию
+type defaultLogger struct {
+	l Logger
+}
+
+// _defaultLogger
+// Logger по умолчанию, может заменяться конкурентно, например, из параллельных тестов
+var _defaultLogger atomic.Pointer[defaultLogger]
+
+func init() {
+	_defaultLogger.Store(&defaultLogger{l: NewLogger()})
+}
 
 func SetDefault(l Logger) {
 	if l == nil {
 		return
 	}
 
-	_defaultLogger = l
+	_defaultLogger.Store(&defaultLogger{l: l})
 }
 
 func GetDefault() Logger {
-	return _defaultLogger
+	return _defaultLogger.Load().l
 }
 
 func defaultLoggerFor(ctx context.Context) Logger {
@@ -99,7 +118,7 @@ func defaultLoggerFor(ctx context.Context) Logger {
 		return l
 	}
 
-	return _defaultLogger
+	return GetDefault()
 }
 
 // Format
@@ -118,6 +137,26 @@ const (
 	// FormatLogFmt
 	// Позволяет записывать логи в LogFmt, формат общий для всех реализаций, но порядок атрибутов может отличаться
 	FormatLogFmt
+
+	// FormatSyslog5424
+	// Позволяет записывать логи в формате syslog RFC 5424, аргументы записываются в элемент структурированных данных.
+	// Формат общий для всех реализаций
+	FormatSyslog5424
+
+	// FormatSyslog3164
+	// Позволяет записывать логи в формате syslog RFC 3164, аргументы добавляются к сообщению в виде key=value.
+	// Формат общий для всех реализаций
+	FormatSyslog3164
+
+	// FormatJournal
+	// Позволяет записывать логи в нативном протоколе journald: аргументы записываются в поля журнала, источник - в поля
+	// CODE_FILE, CODE_LINE и CODE_FUNC. Предназначен для записи через sink.Journal, формат общий для всех реализаций
+	FormatJournal
+
+	// FormatGELF
+	// Позволяет записывать логи в формате GELF 1.1 (Graylog): сообщение записывается в short_message, аргументы - в
+	// дополнительные поля с префиксом '_'. Предназначен для записи через sink.GELF, формат общий для всех реализаций
+	FormatGELF
 )
 
 func (f Format) String() string {
@@ -128,13 +167,62 @@ func (f Format) String() string {
 		return "json"
 	case FormatLogFmt:
 		return "logfmt"
+	case FormatSyslog5424:
+		return "syslog5424"
+	case FormatSyslog3164:
+		return "syslog3164"
+	case FormatJournal:
+		return "journal"
+	case FormatGELF:
+		return "gelf"
 	default:
 		return fmt.Sprintf("Format<%d>", f)
 	}
 }
 
 func (f Format) IsValid() bool {
-	return f >= FormatText && f <= FormatLogFmt
+	return f >= FormatText && f <= FormatGELF
+}
+
+// MarshalText
+// Реализует encoding.TextMarshaler, результат совпадает с Format.String. Для некорректного Format возвращает ошибку
+func (f Format) MarshalText() ([]byte, error) {
+	if !f.IsValid() {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidFormat, f)
+	}
+
+	return []byte(f.String()), nil
+}
+
+// UnmarshalText
+// Реализует encoding.TextUnmarshaler, разбор выполняется с помощью ParseFormat
+func (f *Format) UnmarshalText(text []byte) error {
+	format, err := ParseFormat(string(text))
+	if err != nil {
+		return err
+	}
+
+	*f = format
+	return nil
+}
+
+// Set
+// Реализует flag.Value, разбор выполняется с помощью ParseFormat
+func (f *Format) Set(s string) error {
+	return f.UnmarshalText([]byte(s))
+}
+
+// ParseFormat
+// Разбирает наименование формата без учета регистра: "text", "json", "logfmt", "syslog5424", "syslog3164",
+// "journal", "gelf"
+func ParseFormat(s string) (Format, error) {
+	for f := FormatText; f.IsValid(); f++ {
+		if strings.EqualFold(f.String(), s) {
+			return f, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: %q", ErrInvalidFormat, s)
 }
 
 type Options struct {
@@ -154,6 +242,10 @@ type Options struct {
 	// Наименьший уровень логов, которые допустимо записывать, по умолчанию - LevelDebug
 	Level Level
 
+	// LevelNames
+	// Наименования уровней, переопределяющие зарегистрированные с помощью RegisterLevel, по умолчанию - пустой набор
+	LevelNames map[Level]string
+
 	// SourceKey
 	// Ключ для записи источника, по умолчанию - SourceKey
 	SourceKey string
@@ -162,6 +254,10 @@ type Options struct {
 	// Флаг добавления источника к логу
 	AddSource bool
 
+	// SourceFormat
+	// Формат записи источника, по умолчанию - SourceFormatShort
+	SourceFormat SourceFormat
+
 	// Skip
 	// Кол-во вызовов для пропуска, может использоваться для библиотечных вызовов по умолчанию - 0
 	Skip int
@@ -177,6 +273,69 @@ type Options struct {
 	// MessageKey
 	// Ключ для записи текстового сообщения в лог, по умолчанию - MessageKey
 	MessageKey string
+
+	// ErrorKey
+	// Ключ для записи текста ошибки, переданной в Logger.Warn и Logger.Error, по умолчанию - ErrorKey
+	ErrorKey string
+
+	// Schema
+	// Схема полей лога, по умолчанию - SchemaDefault
+	Schema Schema
+
+	// DuplicateKeys
+	// Политика обработки аргументов с повторяющимися ключами, по умолчанию - DuplicateKeysKeep
+	DuplicateKeys DuplicateKeys
+
+	// NameKey
+	// Ключ для записи имени Logger, заданного с помощью Logger.Named, по умолчанию - NameKey
+	NameKey string
+
+	// Hooks
+	// Функции, вызываемые перед записью каждого лога, по умолчанию - пустой набор
+	Hooks []Hook
+
+	// WriteHooks
+	// Функции, вызываемые после записи каждого лога с записанными байтами, по умолчанию - пустой набор
+	WriteHooks []WriteHook
+
+	// Stats
+	// Получатель событий записи логов, по умолчанию - nil
+	Stats Stats
+
+	// ErrorHandler
+	// Функция, вызываемая при ошибке энкодера или потока вывода, по умолчанию - nil
+	ErrorHandler func(err error)
+
+	// FallbackWriter
+	// Резервный поток вывода, используемый после FallbackFailures последовательных ошибок записи в Writer,
+	// по умолчанию - nil
+	FallbackWriter io.Writer
+
+	// FallbackFailures
+	// Кол-во последовательных ошибок записи в Writer для переключения на FallbackWriter, по умолчанию - 1
+	FallbackFailures int
+
+	// FallbackRetry
+	// Интервал попыток вернуться к Writer после переключения на FallbackWriter, по умолчанию - DefaultFallbackRetry
+	FallbackRetry time.Duration
+
+	// SyslogFacility
+	// Источник сообщений для форматов FormatSyslog5424 и FormatSyslog3164, по умолчанию - FacilityUser
+	SyslogFacility Facility
+
+	// SyslogAppName
+	// Название приложения для форматов FormatSyslog5424 и FormatSyslog3164, по умолчанию - название исполняемого файла
+	SyslogAppName string
+
+	// SyslogStructuredDataID
+	// Идентификатор элемента структурированных данных для формата FormatSyslog5424, по умолчанию -
+	// SyslogStructuredDataID
+	SyslogStructuredDataID string
+
+	// fallback
+	// Состояние переключения на FallbackWriter, общее для Logger и производных от него. Создается при первом
+	// создании Logger после изменения Writer или FallbackWriter
+	fallback *fallbackState
 }
 
 // Option
@@ -210,6 +369,18 @@ func WithLevel(key string, level Level) Option {
 	}
 }
 
+// WithLevelNames
+// Переопределяет наименования уровней при записи логов. Уровни, отсутствующие в names, записываются под
+// зарегистрированными наименованиями. Не влияет на ParseLevel, для этого используйте RegisterLevel
+func WithLevelNames(names map[Level]string) Option {
+	names = maps.Clone(names)
+
+	return func(o Options) Options {
+		o.LevelNames = names
+		return o
+	}
+}
+
 // WithSource
 // Включает запись источника по указанному ключу
 func WithSource(key string) Option {
@@ -260,21 +431,96 @@ func WithMessageKey(key string) Option {
 	}
 }
 
+// WithErrorKey
+// Устанавливает ключ для записи текста ошибки, переданной в Logger.Warn и Logger.Error
+func WithErrorKey(key string) Option {
+	if len(key) == 0 {
+		key = ErrorKey
+	}
+
+	return func(o Options) Options {
+		o.ErrorKey = key
+		return o
+	}
+}
+
+// WithNameKey
+// Устанавливает ключ для записи имени Logger, заданного с помощью Logger.Named
+func WithNameKey(key string) Option {
+	if len(key) == 0 {
+		key = NameKey
+	}
+
+	return func(o Options) Options {
+		o.NameKey = key
+		return o
+	}
+}
+
 // WithWriter
 // Устанавливает поток вывода логов
 func WithWriter(w io.Writer) Option {
 	return func(o Options) Options {
 		o.Writer = w
+		o.fallback = nil
 		return o
 	}
 }
 
+// errorArg
+// Возвращает аргумент с текстом ошибки под ключом ErrorKey
+func (o *Options) errorArg(err error) Arg {
+	a := Err(err)
+	if len(o.ErrorKey) > 0 && o.ErrorKey != ErrorKey {
+		a = renameArg(a, o.ErrorKey)
+	}
+
+	return a
+}
+
+// close
+// Сбрасывает на диск Writer и FallbackWriter, реализующие Sync() error, например, *os.File, и закрывает реализующие
+// io.Closer, кроме os.Stdout и os.Stderr
+func (o *Options) close() error {
+	var errs []error
+
+	for _, w := range []io.Writer{o.Writer, o.FallbackWriter} {
+		if w == os.Stdout || w == os.Stderr {
+			continue
+		}
+
+		if s, ok := w.(interface{ Sync() error }); ok {
+			errs = append(errs, s.Sync())
+		}
+
+		if c, ok := w.(io.Closer); ok {
+			errs = append(errs, c.Close())
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
 // emptyOption
 // Пустая опция, возвращающая исходный Options
 func emptyOption(o Options) Options {
 	return o
 }
 
+// _nameSeparator
+// Разделитель имен в иерархии Logger
+const _nameSeparator = "."
+
+// joinName
+// Добавляет имя к имени родительского Logger
+func joinName(parent, name string) string {
+	if len(parent) == 0 {
+		return name
+	}
+
+	return parent + _nameSeparator + name
+}
+
 // NoContext
 // "Пустой" context.Context без аргументов и Logger
 var NoContext = context.Background()
@@ -303,6 +549,15 @@ func defaultOptions() Options {
 		TimeKey:    TimeKey,
 		TimeFormat: time.RFC3339,
 		MessageKey: MessageKey,
+		ErrorKey:   ErrorKey,
+
+		DuplicateKeys: DuplicateKeysKeep,
+		NameKey:       NameKey,
+
+		FallbackFailures: 1,
+		FallbackRetry:    DefaultFallbackRetry,
+
+		SyslogFacility: FacilityUser,
 	}
 }
 
@@ -322,6 +577,11 @@ type Logger interface {
 	// Если opts отсутствуют, допустимо возвращать текущий Logger без изменений
 	WithOptions(opts ...Option) Logger
 
+	// Named
+	// Создает новый Logger с именем, добавленным к имени текущего Logger через точку: "api" -> "api.users".
+	// Имя записывается в каждый лог по ключу Options.NameKey. Если name пустое, допустимо возвращать текущий Logger
+	Named(name string) Logger
+
 	// Trace
 	// Записывает TRACE-лог с указанным сообщением и аргументами, а также аргументами, переданными в ctx.
 	// Если уровень Logger выше TRACE, то лог должен быть проигнорирован без обработки.
@@ -351,4 +611,19 @@ type Logger interface {
 	// Записывает лог с указанным уровнем, сообщением и аргументами, а также аргументами, переданными в ctx.
 	// Если уровень Logger выше указанного, то лог должен быть проигнорирован без обработки.
 	Write(ctx context.Context, level Level, msg string, args ...Arg)
+
+	// Enabled
+	// Сообщает, будет ли записан лог с указанным уровнем. Позволяет не вычислять дорогостоящие аргументы для
+	// отключенных уровней
+	Enabled(ctx context.Context, level Level) bool
+
+	// Sugar
+	// Возвращает SugaredLogger на основе текущего Logger
+	Sugar() SugaredLogger
+
+	// Close
+	// Сбрасывает потоки вывода Writer и FallbackWriter, реализующие Sync() error, и закрывает реализующие io.Closer
+	// (кроме os.Stdout и os.Stderr), дожидаясь отправки накопленных ими логов, например, sink.HTTP. Вызывается
+	// однократно для корневого Logger при завершении работы, после Close записывать логи нельзя
+	Close() error
 }
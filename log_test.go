@@ -0,0 +1,110 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// decodeEntries
+// Разбирает логи, записанные в формате FormatJSON
+func decodeEntries(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var entries []map[string]any
+
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var entry map[string]any
+		require.NoError(t, dec.Decode(&entry))
+
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+func Test_Logger_Enabled(t *testing.T) {
+	t.Parallel()
+
+	l := NewLogger(WithWriter(&bytes.Buffer{}), WithLevel("", LevelInfo))
+
+	assert.False(t, l.Enabled(NoContext, LevelTrace))
+	assert.False(t, l.Enabled(NoContext, LevelDebug))
+	assert.True(t, l.Enabled(NoContext, LevelInfo))
+	assert.True(t, l.Enabled(NoContext, LevelInfo+1))
+	assert.True(t, l.Enabled(NoContext, LevelWarn))
+	assert.True(t, l.Enabled(NoContext, LevelError))
+}
+
+func Test_Lazy(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	l := NewLogger(WithFormat(FormatJSON), WithWriter(buf), WithLevel("", LevelInfo))
+
+	calls := 0
+	value := func() any {
+		calls++
+		return "value"
+	}
+	args := func() []Arg {
+		calls++
+		return []Arg{String("a", "1"), Int("b", 2)}
+	}
+
+	l.Debug(NoContext, "disabled", Lazy("lazy", value), LazyArgs(args))
+	assert.Zero(t, calls)
+	assert.Zero(t, buf.Len())
+
+	l.Info(NoContext, "enabled", Lazy("lazy", value), LazyArgs(args))
+	assert.Equal(t, 2, calls)
+
+	entries := decodeEntries(t, buf)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "value", entries[0]["lazy"])
+	assert.Equal(t, "1", entries[0]["a"])
+	assert.InDelta(t, 2, entries[0]["b"], 0)
+}
+
+// closeWriter
+// Поток вывода, сообщающий о сбросе и закрытии
+type closeWriter struct {
+	bytes.Buffer
+	synced int
+	closed bool
+}
+
+func (w *closeWriter) Sync() error {
+	w.synced++
+	return nil
+}
+
+func (w *closeWriter) Close() error {
+	w.closed = true
+	return nil
+}
+
+func Test_Logger_Close(t *testing.T) {
+	t.Parallel()
+
+	w, fallback := &closeWriter{}, &closeWriter{}
+
+	l := NewLogger(WithWriter(w), WithFallbackWriter(fallback, 1))
+	l.Info(NoContext, "msg")
+	l.Warn(NoContext, assert.AnError, "warn")
+	l.Error(NoContext, assert.AnError, "error")
+	assert.Zero(t, w.synced, "output must not be synced after each entry")
+
+	require.NoError(t, l.Close())
+	assert.Equal(t, 1, w.synced)
+	assert.True(t, w.closed)
+	assert.Equal(t, 1, fallback.synced)
+	assert.True(t, fallback.closed)
+
+	require.NoError(t, NewLogger(WithWriter(os.Stdout)).Close())
+}
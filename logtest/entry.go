@@ -0,0 +1,166 @@
+package logtest
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/anticrew/log"
+)
+
+// Entry
+// Лог, записанный Logger, созданным NewObserver
+type Entry struct {
+	// Level
+	// Уровень лога
+	Level log.Level
+
+	// Time
+	// Временная метка записи лога
+	Time time.Time
+
+	// Message
+	// Текстовое сообщение
+	Message string
+
+	// Name
+	// Полное имя Logger, заданное с помощью log.Logger.Named
+	Name string
+
+	// Args
+	// Итоговый набор аргументов в порядке записи: аргументы Logger, аргументы из context.Context, аргументы вызова и
+	// ошибка под ключом log.ErrorKey. Аргументы, созданные log.LazyArgs, раскрываются
+	Args []log.Arg
+
+	// Source
+	// Источник записи лога в компактном формате: "pkg/file.go:line function"
+	Source string
+}
+
+// Arg
+// Возвращает значение последнего аргумента с указанным ключом и статус его наличия
+func (e Entry) Arg(key string) (any, bool) {
+	for i := len(e.Args) - 1; i >= 0; i-- {
+		if log.ArgKey(e.Args[i]) == key {
+			return log.ArgValue(e.Args[i]), true
+		}
+	}
+
+	return nil, false
+}
+
+// ArgsMap
+// Возвращает аргументы в виде map, при повторении ключа сохраняется последнее значение
+func (e Entry) ArgsMap() map[string]any {
+	m := make(map[string]any, len(e.Args))
+	for _, arg := range e.Args {
+		m[log.ArgKey(arg)] = log.ArgValue(arg)
+	}
+
+	return m
+}
+
+// HasArg
+// Сообщает, содержит ли лог аргумент с указанным ключом и значением. Числовые значения сравниваются без учета
+// конкретного типа, поэтому результат не зависит от драйвера
+func (e Entry) HasArg(key string, value any) bool {
+	for _, arg := range e.Args {
+		if log.ArgKey(arg) == key && equalValues(log.ArgValue(arg), value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Entries
+// Набор записанных логов
+type Entries []Entry
+
+// Filter
+// Возвращает логи, для которых fn возвращает true
+func (e Entries) Filter(fn func(Entry) bool) Entries {
+	var filtered Entries
+
+	for _, entry := range e {
+		if fn(entry) {
+			filtered = append(filtered, entry)
+		}
+	}
+
+	return filtered
+}
+
+// FilterMessage
+// Возвращает логи с указанным сообщением
+func (e Entries) FilterMessage(msg string) Entries {
+	return e.Filter(func(entry Entry) bool {
+		return entry.Message == msg
+	})
+}
+
+// FilterLevel
+// Возвращает логи с указанным уровнем
+func (e Entries) FilterLevel(level log.Level) Entries {
+	return e.Filter(func(entry Entry) bool {
+		return entry.Level == level
+	})
+}
+
+// FilterName
+// Возвращает логи, записанные Logger с указанным полным именем
+func (e Entries) FilterName(name string) Entries {
+	return e.Filter(func(entry Entry) bool {
+		return entry.Name == name
+	})
+}
+
+// FilterArg
+// Возвращает логи, содержащие аргумент с указанным ключом и значением
+func (e Entries) FilterArg(key string, value any) Entries {
+	return e.Filter(func(entry Entry) bool {
+		return entry.HasArg(key, value)
+	})
+}
+
+// FilterArgKey
+// Возвращает логи, содержащие аргумент с указанным ключом
+func (e Entries) FilterArgKey(key string) Entries {
+	return e.Filter(func(entry Entry) bool {
+		_, ok := entry.Arg(key)
+		return ok
+	})
+}
+
+// equalValues
+// Сравнивает значения аргументов, приводя числа к общему типу: драйверы по-разному хранят числовые значения
+func equalValues(a, b any) bool {
+	return reflect.DeepEqual(normalize(a), normalize(b))
+}
+
+// normalize
+// Приводит целые числа к int64 (или uint64, если значение не помещается в int64), а числа с плавающей точкой к float64
+func normalize(v any) any {
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if _, ok := v.(time.Duration); ok {
+			return v
+		}
+
+		return rv.Int()
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if u := rv.Uint(); u <= uint64(1<<63-1) {
+			return int64(u)
+		}
+
+		return rv.Uint()
+
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+
+	default:
+		return v
+	}
+}
@@ -0,0 +1,274 @@
+package logtest
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/anticrew/log"
+)
+
+// Options
+// Настройки Logger, созданного New
+type Options struct {
+	// LogOptions
+	// Опции Logger, применяемые после опций по умолчанию: log.LevelTrace и вывод через testing.TB.Log
+	LogOptions []log.Option
+
+	// FailLevel
+	// Уровень, начиная с которого запись лога помечает тест как проваленный. По умолчанию тест не проваливается
+	FailLevel log.Level
+
+	// Fail
+	// Флаг проваливания теста при записи лога с уровнем FailLevel и выше
+	Fail bool
+
+	// SetDefault
+	// Флаг установки Logger в качестве Logger по умолчанию на время теста, по умолчанию - true
+	SetDefault bool
+}
+
+// Option
+// Опция-функция для настройки Logger, созданного New
+type Option func(o Options) Options
+
+// WithLogOptions
+// Добавляет опции Logger. Опция log.WithWriter перенаправит вывод из testing.TB.Log
+func WithLogOptions(options ...log.Option) Option {
+	return func(o Options) Options {
+		o.LogOptions = append(o.LogOptions, options...)
+		return o
+	}
+}
+
+// WithFailOnError
+// Помечает тест как проваленный при записи лога с уровнем log.LevelError и выше
+func WithFailOnError() Option {
+	return WithFailLevel(log.LevelError)
+}
+
+// WithFailLevel
+// Помечает тест как проваленный при записи лога с указанным уровнем и выше
+func WithFailLevel(level log.Level) Option {
+	return func(o Options) Options {
+		o.FailLevel = level
+		o.Fail = true
+		return o
+	}
+}
+
+// WithoutDefault
+// Отключает установку Logger в качестве Logger по умолчанию
+func WithoutDefault() Option {
+	return func(o Options) Options {
+		o.SetDefault = false
+		return o
+	}
+}
+
+// New
+// Создает Logger, записывающий каждый лог через t.Log, чтобы вывод был привязан к тесту и не смешивался с выводом
+// параллельных тестов. По умолчанию Logger устанавливается как Logger по умолчанию (log.SetDefault), а по завершении
+// теста с помощью t.Cleanup восстанавливается предыдущий. Параллельные тесты разделяют Logger по умолчанию, поэтому
+// для них рекомендуется WithoutDefault и передача Logger явно или через log.SetContextLogger
+func New(t testing.TB, options ...Option) log.Logger {
+	t.Helper()
+
+	opt := Options{
+		SetDefault: true,
+	}
+
+	for _, o := range options {
+		opt = o(opt)
+	}
+
+	w := &testWriter{
+		t: t,
+	}
+
+	logOptions := make([]log.Option, 0, len(opt.LogOptions)+2)
+	logOptions = append(logOptions,
+		log.WithWriter(w),
+		log.WithLevel("", log.LevelTrace),
+	)
+	logOptions = append(logOptions, opt.LogOptions...)
+
+	l := &testLogger{
+		Logger: log.AddCallerSkip(log.NewLogger(logOptions...), 1), // skip testLogger method
+		w:      w,
+		opt:    opt,
+	}
+
+	if opt.SetDefault {
+		prev := log.GetDefault()
+		log.SetDefault(l)
+
+		t.Cleanup(func() {
+			log.SetDefault(prev)
+		})
+	}
+
+	t.Cleanup(w.finish)
+
+	return l
+}
+
+// testWriter
+// Поток вывода, накапливающий логи до вызова flush из метода testLogger, чтобы testing.TB.Log указывал на место
+// вызова Logger. После завершения теста перенаправляет вывод в os.Stderr, так как testing.TB.Log после завершения
+// теста приводит к панике
+type testWriter struct {
+	t testing.TB
+
+	mu      sync.Mutex
+	pending []string
+	done    bool
+}
+
+func (w *testWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.done {
+		return os.Stderr.Write(p)
+	}
+
+	w.pending = append(w.pending, strings.TrimSuffix(string(p), "\n"))
+	return len(p), nil
+}
+
+// flush
+// Записывает накопленные логи через testing.TB.Log. При конкурентной записи лог может быть записан вызовом flush
+// из другой горутины, тогда testing.TB.Log укажет на место ее вызова
+func (w *testWriter) flush() {
+	w.t.Helper()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, line := range w.pending {
+		w.t.Log(line)
+	}
+
+	w.pending = nil
+}
+
+// fail
+// Помечает тест как проваленный, если он еще не завершен
+func (w *testWriter) fail(level log.Level, msg string) {
+	w.t.Helper()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.done {
+		w.t.Errorf("logged %s entry: %s", level, msg)
+	}
+}
+
+func (w *testWriter) finish() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, line := range w.pending {
+		_, _ = os.Stderr.WriteString(line + "\n")
+	}
+
+	w.pending = nil
+	w.done = true
+}
+
+// testLogger
+// Logger, записывающий логи через testing.TB и при необходимости проваливающий тест
+type testLogger struct {
+	log.Logger
+
+	w   *testWriter
+	opt Options
+}
+
+func (l *testLogger) wrap(origin log.Logger) log.Logger {
+	return &testLogger{
+		Logger: origin,
+		w:      l.w,
+		opt:    l.opt,
+	}
+}
+
+func (l *testLogger) WithArgs(args ...log.Arg) log.Logger {
+	return l.wrap(l.Logger.WithArgs(args...))
+}
+
+func (l *testLogger) WithContext(ctx context.Context) log.Logger {
+	return l.wrap(l.Logger.WithContext(ctx))
+}
+
+func (l *testLogger) WithOptions(options ...log.Option) log.Logger {
+	return l.wrap(l.Logger.WithOptions(options...))
+}
+
+func (l *testLogger) Named(name string) log.Logger {
+	return l.wrap(l.Logger.Named(name))
+}
+
+func (l *testLogger) WithCallerSkip(skip int) log.Logger {
+	return l.wrap(log.AddCallerSkip(l.Logger, skip))
+}
+
+func (l *testLogger) Sugar() log.SugaredLogger {
+	return log.NewSugaredLogger(l)
+}
+
+func (l *testLogger) Trace(ctx context.Context, msg string, args ...log.Arg) {
+	l.w.t.Helper()
+	l.Logger.Trace(ctx, msg, args...)
+	l.w.flush()
+	l.check(ctx, log.LevelTrace, msg)
+}
+
+func (l *testLogger) Debug(ctx context.Context, msg string, args ...log.Arg) {
+	l.w.t.Helper()
+	l.Logger.Debug(ctx, msg, args...)
+	l.w.flush()
+	l.check(ctx, log.LevelDebug, msg)
+}
+
+func (l *testLogger) Info(ctx context.Context, msg string, args ...log.Arg) {
+	l.w.t.Helper()
+	l.Logger.Info(ctx, msg, args...)
+	l.w.flush()
+	l.check(ctx, log.LevelInfo, msg)
+}
+
+func (l *testLogger) Warn(ctx context.Context, err error, msg string, args ...log.Arg) {
+	l.w.t.Helper()
+	l.Logger.Warn(ctx, err, msg, args...)
+	l.w.flush()
+	l.check(ctx, log.LevelWarn, msg)
+}
+
+func (l *testLogger) Error(ctx context.Context, err error, msg string, args ...log.Arg) {
+	l.w.t.Helper()
+	l.Logger.Error(ctx, err, msg, args...)
+	l.w.flush()
+	l.check(ctx, log.LevelError, msg)
+}
+
+func (l *testLogger) Write(ctx context.Context, level log.Level, msg string, args ...log.Arg) {
+	l.w.t.Helper()
+	l.Logger.Write(ctx, level, msg, args...)
+	l.w.flush()
+	l.check(ctx, level, msg)
+}
+
+// check
+// Проваливает тест, если лог с указанным уровнем был записан и уровень не ниже Options.FailLevel
+func (l *testLogger) check(ctx context.Context, level log.Level, msg string) {
+	l.w.t.Helper()
+
+	if l.opt.Fail && level >= l.opt.FailLevel && l.Logger.Enabled(ctx, level) {
+		l.w.fail(level, msg)
+	}
+}
@@ -0,0 +1,131 @@
+package logtest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/anticrew/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingT
+// Реализация testing.TB, сохраняющая вывод, ошибки и функции очистки
+type recordingT struct {
+	testing.TB
+
+	lines    []string
+	errors   []string
+	cleanups []func()
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Log(args ...any) {
+	for _, arg := range args {
+		r.lines = append(r.lines, arg.(string)) //nolint:forcetypeassert // testWriter always logs strings
+	}
+}
+
+func (r *recordingT) Errorf(format string, _ ...any) {
+	r.errors = append(r.errors, format)
+}
+
+func (r *recordingT) Cleanup(fn func()) {
+	r.cleanups = append(r.cleanups, fn)
+}
+
+func (r *recordingT) finish() {
+	for i := len(r.cleanups) - 1; i >= 0; i-- {
+		r.cleanups[i]()
+	}
+}
+
+//nolint:paralleltest // replaces default logger
+func Test_New(t *testing.T) {
+	prev := log.GetDefault()
+	rt := &recordingT{}
+
+	l := New(rt, WithLogOptions(log.WithFormat(log.FormatLogFmt)))
+	assert.Same(t, l, log.GetDefault())
+
+	log.Info(log.NoContext, "via default", log.String("key", "value"))
+	l.WithArgs(log.Int("n", 1)).Error(log.NoContext, assert.AnError, "failure")
+
+	require.Len(t, rt.lines, 2)
+	assert.Contains(t, rt.lines[0], "via default")
+	assert.Contains(t, rt.lines[0], "key=value")
+	assert.NotContains(t, rt.lines[0], "\n")
+	assert.Contains(t, rt.lines[1], "failure")
+	assert.Contains(t, rt.lines[1], "n=1")
+	assert.Empty(t, rt.errors)
+
+	rt.finish()
+	assert.Same(t, prev, log.GetDefault())
+}
+
+func Test_New_FailOnError(t *testing.T) {
+	t.Parallel()
+
+	rt := &recordingT{}
+
+	l := New(rt, WithFailOnError(), WithoutDefault(), WithLogOptions(log.WithLevel("", log.LevelInfo)))
+	l.Warn(log.NoContext, nil, "warn")
+	assert.Empty(t, rt.errors)
+
+	l.WithContext(log.NoContext).Error(log.NoContext, assert.AnError, "error")
+	l.Write(log.NoContext, log.LevelError+1, "error+1")
+	assert.Len(t, rt.errors, 2)
+
+	l.WithOptions(log.WithLevel("", log.LevelError+4)).Error(log.NoContext, assert.AnError, "disabled")
+	assert.Len(t, rt.errors, 2)
+
+	rt.finish()
+
+	l.Error(log.NoContext, assert.AnError, "after finish")
+	assert.Len(t, rt.errors, 2)
+	assert.Len(t, rt.lines, 3)
+}
+
+func Test_New_Sugar(t *testing.T) {
+	t.Parallel()
+
+	rt := &recordingT{}
+
+	l := New(rt, WithFailOnError(), WithoutDefault(), WithLogOptions(log.WithFormat(log.FormatLogFmt)))
+	l.Sugar().Infow(log.NoContext, "sugared", "n", 1)
+	l.Sugar().Errorf(log.NoContext, assert.AnError, "failure %d", 2)
+
+	require.Len(t, rt.lines, 2)
+	assert.Contains(t, rt.lines[0], "n=1")
+	assert.Contains(t, rt.lines[1], "failure 2")
+	assert.Len(t, rt.errors, 1)
+
+	rt.finish()
+}
+
+func Test_New_Source(t *testing.T) {
+	t.Parallel()
+
+	rt := &recordingT{}
+
+	l := New(rt, WithoutDefault(), WithLogOptions(
+		log.WithFormat(log.FormatLogFmt),
+		log.WithSource("source"),
+		log.WithSkip(0),
+	))
+
+	ctx := log.SetContextLogger(context.Background(), l)
+
+	l.Info(ctx, "direct")
+	l.Sugar().Infof(ctx, "sugared %d", 1)
+	log.Ctx(ctx).Info("context")
+	log.Infow(ctx, "package sugared")
+
+	require.Len(t, rt.lines, 4)
+	for _, line := range rt.lines {
+		assert.Contains(t, line, `source="logtest/logger_test.go:`, line)
+	}
+
+	rt.finish()
+}
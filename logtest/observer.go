@@ -0,0 +1,377 @@
+package logtest
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anticrew/log"
+	"github.com/anticrew/log/internal/caller"
+)
+
+// Recorder
+// Хранилище логов, записанных Logger, созданным NewObserver. Безопасно для конкурентного использования
+type Recorder struct {
+	mu      sync.RWMutex
+	entries Entries
+}
+
+// NewObserver
+// Создает Logger, сохраняющий логи в Recorder в структурированном виде вместо записи в поток вывода. Из опций
+// учитываются только минимальный уровень (по умолчанию - log.LevelTrace), кол-во пропущенных вызовов при определении
+// источника, формат источника и log.Hook. Поведение не зависит от выбранного драйвера
+func NewObserver(options ...log.Option) (log.Logger, *Recorder) {
+	opt := log.Options{
+		Level: log.LevelTrace,
+	}
+
+	for _, o := range options {
+		opt = o(opt)
+	}
+
+	rec := &Recorder{}
+
+	return &observer{
+		rec: rec,
+		opt: opt,
+	}, rec
+}
+
+// All
+// Возвращает копию всех записанных логов
+func (r *Recorder) All() Entries {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return slices.Clone(r.entries)
+}
+
+// TakeAll
+// Возвращает все записанные логи и очищает Recorder
+func (r *Recorder) TakeAll() Entries {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries := r.entries
+	r.entries = nil
+
+	return entries
+}
+
+// Len
+// Возвращает кол-во записанных логов
+func (r *Recorder) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.entries)
+}
+
+// Reset
+// Удаляет все записанные логи
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries = nil
+}
+
+// FilterMessage
+// Возвращает логи с указанным сообщением
+func (r *Recorder) FilterMessage(msg string) Entries {
+	return r.All().FilterMessage(msg)
+}
+
+// FilterLevel
+// Возвращает логи с указанным уровнем
+func (r *Recorder) FilterLevel(level log.Level) Entries {
+	return r.All().FilterLevel(level)
+}
+
+// FilterName
+// Возвращает логи, записанные Logger с указанным полным именем
+func (r *Recorder) FilterName(name string) Entries {
+	return r.All().FilterName(name)
+}
+
+// FilterArg
+// Возвращает логи, содержащие аргумент с указанным ключом и значением
+func (r *Recorder) FilterArg(key string, value any) Entries {
+	return r.All().FilterArg(key, value)
+}
+
+// AssertLogged
+// Проверяет, что был записан лог с указанным уровнем и сообщением, содержащий все указанные аргументы. В случае
+// отсутствия такого лога помечает тест как проваленный и возвращает false
+func (r *Recorder) AssertLogged(t testing.TB, level log.Level, msg string, args ...log.Arg) bool {
+	t.Helper()
+
+	if len(r.find(level, msg, args)) > 0 {
+		return true
+	}
+
+	t.Errorf("expected %s entry %q with args %s, got:\n%s", level, msg, formatArgs(args), r.All())
+	return false
+}
+
+// RequireLogged
+// Аналог AssertLogged, завершающий тест в случае отсутствия лога. Возвращает первый подходящий лог
+func (r *Recorder) RequireLogged(t testing.TB, level log.Level, msg string, args ...log.Arg) Entry {
+	t.Helper()
+
+	entries := r.find(level, msg, args)
+	if len(entries) == 0 {
+		t.Fatalf("expected %s entry %q with args %s, got:\n%s", level, msg, formatArgs(args), r.All())
+	}
+
+	return entries[0]
+}
+
+// AssertNotLogged
+// Проверяет, что лог с указанным уровнем и сообщением не был записан
+func (r *Recorder) AssertNotLogged(t testing.TB, level log.Level, msg string) bool {
+	t.Helper()
+
+	if entries := r.All().FilterLevel(level).FilterMessage(msg); len(entries) > 0 {
+		t.Errorf("unexpected %s entry %q:\n%s", level, msg, entries)
+		return false
+	}
+
+	return true
+}
+
+func (r *Recorder) find(level log.Level, msg string, args []log.Arg) Entries {
+	return r.All().FilterLevel(level).FilterMessage(msg).Filter(func(e Entry) bool {
+		for _, arg := range args {
+			if !e.HasArg(log.ArgKey(arg), log.ArgValue(arg)) {
+				return false
+			}
+		}
+
+		return true
+	})
+}
+
+func (r *Recorder) add(e Entry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries = append(r.entries, e)
+}
+
+// String
+// Возвращает логи в текстовом виде, по одному в строке
+func (e Entries) String() string {
+	var sb strings.Builder
+
+	for _, entry := range e {
+		sb.WriteString(entry.String())
+		sb.WriteByte('\n')
+	}
+
+	return sb.String()
+}
+
+// String
+// Возвращает лог в текстовом виде
+func (e Entry) String() string {
+	return fmt.Sprintf("%s %q %s", e.Level, e.Message, formatArgs(e.Args))
+}
+
+func formatArgs(args []log.Arg) string {
+	var sb strings.Builder
+
+	sb.WriteByte('[')
+	for i, arg := range args {
+		if i > 0 {
+			sb.WriteByte(' ')
+		}
+
+		fmt.Fprintf(&sb, "%s=%v", log.ArgKey(arg), log.ArgValue(arg))
+	}
+	sb.WriteByte(']')
+
+	return sb.String()
+}
+
+// observer
+// Реализация Logger, сохраняющая логи в Recorder
+type observer struct {
+	rec        *Recorder
+	opt        log.Options
+	args       []log.Arg
+	name       string
+	bound      log.BoundContext
+	callerSkip int
+}
+
+func (o *observer) WithArgs(args ...log.Arg) log.Logger {
+	if len(args) == 0 {
+		return o
+	}
+
+	return &observer{
+		rec:        o.rec,
+		opt:        o.opt,
+		args:       append(slices.Clip(o.args), args...),
+		name:       o.name,
+		bound:      o.bound,
+		callerSkip: o.callerSkip,
+	}
+}
+
+func (o *observer) WithContext(ctx context.Context) log.Logger {
+	bound, args, ok := o.bound.Bind(ctx)
+	if !ok {
+		return o
+	}
+
+	return &observer{
+		rec:        o.rec,
+		opt:        o.opt,
+		args:       append(slices.Clip(o.args), args...),
+		name:       o.name,
+		bound:      bound,
+		callerSkip: o.callerSkip,
+	}
+}
+
+func (o *observer) WithOptions(options ...log.Option) log.Logger {
+	opt := o.opt
+	for _, option := range options {
+		opt = option(opt)
+	}
+
+	return &observer{
+		rec:        o.rec,
+		opt:        opt,
+		args:       o.args,
+		name:       o.name,
+		bound:      o.bound,
+		callerSkip: o.callerSkip,
+	}
+}
+
+func (o *observer) Named(name string) log.Logger {
+	if len(name) == 0 {
+		return o
+	}
+
+	if len(o.name) > 0 {
+		name = o.name + "." + name
+	}
+
+	return &observer{
+		rec:        o.rec,
+		opt:        o.opt,
+		args:       o.args,
+		name:       name,
+		bound:      o.bound,
+		callerSkip: o.callerSkip,
+	}
+}
+
+func (o *observer) WithCallerSkip(skip int) log.Logger {
+	no := *o
+	no.callerSkip += skip
+
+	return &no
+}
+
+func (o *observer) Sugar() log.SugaredLogger {
+	return log.NewSugaredLogger(o)
+}
+
+func (o *observer) Trace(ctx context.Context, msg string, args ...log.Arg) {
+	o.log(ctx, log.LevelTrace, nil, msg, args)
+}
+
+func (o *observer) Debug(ctx context.Context, msg string, args ...log.Arg) {
+	o.log(ctx, log.LevelDebug, nil, msg, args)
+}
+
+func (o *observer) Info(ctx context.Context, msg string, args ...log.Arg) {
+	o.log(ctx, log.LevelInfo, nil, msg, args)
+}
+
+func (o *observer) Warn(ctx context.Context, err error, msg string, args ...log.Arg) {
+	o.log(ctx, log.LevelWarn, err, msg, args)
+}
+
+func (o *observer) Error(ctx context.Context, err error, msg string, args ...log.Arg) {
+	o.log(ctx, log.LevelError, err, msg, args)
+}
+
+func (o *observer) Write(ctx context.Context, level log.Level, msg string, args ...log.Arg) {
+	o.log(ctx, level, nil, msg, args)
+}
+
+func (o *observer) Enabled(_ context.Context, level log.Level) bool {
+	return level >= o.opt.Level
+}
+
+func (o *observer) Close() error {
+	return nil
+}
+
+func (o *observer) log(ctx context.Context, level log.Level, err error, msg string, args []log.Arg) {
+	if !o.Enabled(ctx, level) {
+		return
+	}
+
+	ctxArgs := o.bound.AppendArgs(nil, ctx)
+
+	merged := make([]log.Arg, 0, len(o.args)+len(ctxArgs)+len(args)+1)
+	merged = appendArgs(merged, o.args)
+	merged = appendArgs(merged, ctxArgs)
+	merged = appendArgs(merged, args)
+
+	if err != nil {
+		merged = append(merged, log.Err(err))
+	}
+
+	// skip log and level-dependent function
+	src, _ := caller.TakeFormat(o.opt.Skip+o.callerSkip+2, caller.Format(o.opt.SourceFormat))
+
+	e := &log.Entry{
+		Level:   level,
+		Time:    time.Now(),
+		Message: msg,
+		Name:    o.name,
+		Args:    merged,
+	}
+
+	for _, hook := range o.opt.Hooks {
+		if err := hook(ctx, e); err != nil {
+			return
+		}
+	}
+
+	o.rec.add(Entry{
+		Level:   e.Level,
+		Time:    e.Time,
+		Message: e.Message,
+		Name:    e.Name,
+		Args:    e.Args,
+		Source:  src,
+	})
+}
+
+// appendArgs
+// Добавляет аргументы в dst, раскрывая аргументы, созданные log.LazyArgs
+func appendArgs(dst, args []log.Arg) []log.Arg {
+	for _, arg := range args {
+		if group, ok := log.ArgValue(arg).([]log.Arg); ok && len(log.ArgKey(arg)) == 0 {
+			dst = appendArgs(dst, group)
+			continue
+		}
+
+		dst = append(dst, arg)
+	}
+
+	return dst
+}
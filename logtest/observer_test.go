@@ -0,0 +1,163 @@
+package logtest
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anticrew/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NewObserver(t *testing.T) {
+	t.Parallel()
+
+	l, rec := NewObserver()
+
+	ctx := log.AddContextArgs(context.Background(), log.String("request_id", "r-1"))
+
+	l.WithArgs(log.String("component", "repo")).Info(ctx, "saved", log.Int("id", 42), log.Duration("took", time.Second))
+	l.Error(ctx, assert.AnError, "failed", log.Uint("attempt", 3))
+	l.Trace(log.NoContext, "trace", log.LazyArgs(func() []log.Arg {
+		return []log.Arg{log.Bool("lazy", true)}
+	}))
+
+	require.Equal(t, 3, rec.Len())
+
+	entry := rec.RequireLogged(t, log.LevelInfo, "saved", log.Int("id", 42))
+	assert.Equal(t, map[string]any{
+		"component":  "repo",
+		"request_id": "r-1",
+		"id":         int64(42),
+		"took":       time.Second,
+	}, entry.ArgsMap())
+	assert.True(t, strings.HasPrefix(entry.Source, "logtest/observer_test.go:"), entry.Source)
+	assert.WithinDuration(t, time.Now(), entry.Time, time.Minute)
+
+	rec.AssertLogged(t, log.LevelError, "failed", log.String(log.ErrorKey, assert.AnError.Error()), log.Int("attempt", 3))
+	rec.AssertNotLogged(t, log.LevelWarn, "failed")
+
+	assert.Len(t, rec.FilterLevel(log.LevelTrace).FilterArg("lazy", true), 1)
+	assert.Len(t, rec.FilterArg("request_id", "r-1"), 2)
+	assert.Len(t, rec.FilterMessage("saved"), 1)
+	assert.Len(t, rec.All().FilterArgKey("component"), 1)
+
+	assert.Len(t, rec.TakeAll(), 3)
+	assert.Zero(t, rec.Len())
+}
+
+func Test_NewObserver_Level(t *testing.T) {
+	t.Parallel()
+
+	l, rec := NewObserver(log.WithLevel("", log.LevelWarn))
+
+	assert.False(t, l.Enabled(log.NoContext, log.LevelInfo))
+	assert.True(t, l.Enabled(log.NoContext, log.LevelWarn))
+
+	l.Info(log.NoContext, "info")
+	l.Warn(log.NoContext, nil, "warn")
+
+	entries := rec.All()
+	require.Len(t, entries, 1)
+	assert.Equal(t, log.LevelWarn, entries[0].Level)
+
+	l.WithOptions(log.WithLevel("", log.LevelInfo)).Info(log.NoContext, "info")
+	assert.Equal(t, 2, rec.Len())
+
+	rec.Reset()
+	assert.Zero(t, rec.Len())
+}
+
+func Test_Recorder_Failures(t *testing.T) {
+	t.Parallel()
+
+	l, rec := NewObserver()
+	l.Info(log.NoContext, "info", log.String("key", "value"))
+
+	mock := &mockT{}
+	assert.False(t, rec.AssertLogged(mock, log.LevelInfo, "info", log.String("key", "other")))
+	assert.False(t, rec.AssertNotLogged(mock, log.LevelInfo, "info"))
+	assert.Equal(t, 2, mock.errors)
+}
+
+// mockT
+// Реализация testing.TB, подсчитывающая ошибки вместо завершения теста
+type mockT struct {
+	testing.TB
+
+	errors int
+}
+
+func (m *mockT) Helper() {}
+
+func (m *mockT) Errorf(string, ...any) {
+	m.errors++
+}
+
+func Test_Observer_Named(t *testing.T) {
+	t.Parallel()
+
+	l, rec := NewObserver()
+
+	l.Named("api").Named("users").Info(log.NoContext, "named")
+	l.Info(log.NoContext, "unnamed")
+
+	entries := rec.FilterName("api.users")
+	require.Len(t, entries, 1)
+	assert.Equal(t, "named", entries[0].Message)
+	assert.Len(t, rec.FilterName(""), 1)
+}
+
+func Test_Observer_Hook(t *testing.T) {
+	t.Parallel()
+
+	l, rec := NewObserver(log.WithHook(func(_ context.Context, e *log.Entry) error {
+		if e.Message == "drop" {
+			return log.ErrDropEntry
+		}
+
+		e.Args = append(e.Args, log.String("tag", "hooked"))
+		return nil
+	}))
+
+	l.Info(log.NoContext, "kept")
+	l.Info(log.NoContext, "drop")
+
+	require.Equal(t, 1, rec.Len())
+	assert.True(t, rec.All()[0].HasArg("tag", "hooked"))
+}
+
+func Test_Observer_WithContext(t *testing.T) {
+	t.Parallel()
+
+	l, rec := NewObserver()
+
+	ctx := log.SetContextLogger(context.Background(), l)
+	ctx = log.With(ctx, log.String("request_id", "r-1"))
+	ctx = log.With(ctx, log.String("component", "api"))
+
+	log.Ctx(ctx).Info("bound")
+	log.From(ctx).Info(ctx, "from")
+
+	for _, msg := range []string{"bound", "from"} {
+		entry := rec.RequireLogged(t, log.LevelInfo, msg)
+		assert.Equal(t, []log.Arg{log.String("request_id", "r-1"), log.String("component", "api")}, entry.Args)
+	}
+}
+
+func Test_Observer_Source(t *testing.T) {
+	t.Parallel()
+
+	l, rec := NewObserver()
+	ctx := log.SetContextLogger(context.Background(), l)
+
+	l.Sugar().Infof(ctx, "sugared %d", 1)
+	log.Ctx(ctx).Info("context")
+
+	require.Equal(t, 2, rec.Len())
+	for _, entry := range rec.All() {
+		assert.True(t, strings.HasPrefix(entry.Source, "logtest/observer_test.go:"), entry.Source)
+	}
+}
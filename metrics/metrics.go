@@ -0,0 +1,142 @@
+package metrics
+
+import (
+	"cmp"
+	"expvar"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/anticrew/log"
+)
+
+// Metrics
+// Счетчики записанных логов, отмененных Hook и не записанных из-за ошибок, по уровню и имени Logger. Реализует
+// log.Stats, публикуется через expvar и http.Handler в текстовом формате Prometheus. Используется с опцией
+// log.WithStats, один экземпляр может использоваться несколькими Logger
+type Metrics struct {
+	entries counters
+	dropped counters
+	errors  counters
+}
+
+var _ log.Stats = (*Metrics)(nil)
+
+// New
+// Создает Metrics с нулевыми счетчиками
+func New() *Metrics {
+	return &Metrics{}
+}
+
+// Written
+// Увеличивает счетчик записанных логов
+func (m *Metrics) Written(level log.Level, name string) {
+	m.entries.inc(level, name)
+}
+
+// Dropped
+// Увеличивает счетчик логов, запись которых отменена Hook
+func (m *Metrics) Dropped(level log.Level, name string) {
+	m.dropped.inc(level, name)
+}
+
+// Failed
+// Увеличивает счетчик логов, не записанных из-за ошибки энкодера или потока вывода
+func (m *Metrics) Failed(level log.Level, name string, _ error) {
+	m.errors.inc(level, name)
+}
+
+// Counter
+// Значение счетчика для уровня и имени Logger
+type Counter struct {
+	Level  string `json:"level"`
+	Logger string `json:"logger"`
+	Value  uint64 `json:"value"`
+}
+
+// Snapshot
+// Значения всех счетчиков, отсортированные по уровню и имени Logger
+type Snapshot struct {
+	Entries []Counter `json:"entries"`
+	Dropped []Counter `json:"dropped"`
+	Errors  []Counter `json:"errors"`
+}
+
+// Snapshot
+// Возвращает текущие значения счетчиков
+func (m *Metrics) Snapshot() Snapshot {
+	return Snapshot{
+		Entries: m.entries.snapshot(),
+		Dropped: m.dropped.snapshot(),
+		Errors:  m.errors.snapshot(),
+	}
+}
+
+// Publish
+// Публикует метрики в expvar под указанным именем. Как и expvar.Publish, вызывает панику при повторной публикации
+// с тем же именем
+func (m *Metrics) Publish(name string) {
+	expvar.Publish(name, expvar.Func(func() any {
+		return m.Snapshot()
+	}))
+}
+
+// counterKey
+// Ключ счетчика: уровень и полное имя Logger
+type counterKey struct {
+	level log.Level
+	name  string
+}
+
+// counters
+// Набор счетчиков по уровню и имени Logger
+type counters struct {
+	m sync.Map // map[counterKey]*atomic.Uint64
+}
+
+func (c *counters) inc(level log.Level, name string) {
+	key := counterKey{
+		level: level,
+		name:  name,
+	}
+
+	v, ok := c.m.Load(key)
+	if !ok {
+		v, _ = c.m.LoadOrStore(key, new(atomic.Uint64))
+	}
+
+	v.(*atomic.Uint64).Add(1) //nolint:forcetypeassert // counters stores only *atomic.Uint64
+}
+
+func (c *counters) snapshot() []Counter {
+	type sample struct {
+		key   counterKey
+		value uint64
+	}
+
+	var samples []sample
+
+	c.m.Range(func(k, v any) bool {
+		samples = append(samples, sample{
+			key:   k.(counterKey),            //nolint:forcetypeassert // counters stores only counterKey
+			value: v.(*atomic.Uint64).Load(), //nolint:forcetypeassert // counters stores only *atomic.Uint64
+		})
+
+		return true
+	})
+
+	slices.SortFunc(samples, func(a, b sample) int {
+		return cmp.Or(cmp.Compare(a.key.level, b.key.level), cmp.Compare(a.key.name, b.key.name))
+	})
+
+	result := make([]Counter, 0, len(samples))
+	for _, s := range samples {
+		result = append(result, Counter{
+			Level:  s.key.level.String(),
+			Logger: s.key.name,
+			Value:  s.value,
+		})
+	}
+
+	return result
+}
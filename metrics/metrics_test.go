@@ -0,0 +1,104 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"expvar"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/anticrew/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failWriter
+// Поток вывода, возвращающий ошибку при каждой записи
+type failWriter struct{}
+
+func (failWriter) Write([]byte) (int, error) {
+	return 0, io.ErrShortWrite
+}
+
+func newMetrics(t *testing.T) *Metrics {
+	t.Helper()
+
+	m := New()
+
+	l := log.NewLogger(log.WithWriter(&bytes.Buffer{}), log.WithStats(m),
+		log.WithHook(func(_ context.Context, e *log.Entry) error {
+			if e.Message == "drop" {
+				return log.ErrDropEntry
+			}
+
+			return nil
+		}),
+	)
+
+	l.Info(log.NoContext, "info")
+	l.Named("api").Info(log.NoContext, "info")
+	l.Named("api").Info(log.NoContext, "info")
+	l.Named("api").Error(log.NoContext, assert.AnError, "error")
+	l.Debug(log.NoContext, "drop")
+	l.Trace(log.NoContext, "disabled")
+
+	l.WithOptions(log.WithWriter(failWriter{})).Named("db").Warn(log.NoContext, nil, "warn")
+
+	return m
+}
+
+func Test_Metrics_Snapshot(t *testing.T) {
+	t.Parallel()
+
+	s := newMetrics(t).Snapshot()
+
+	assert.Equal(t, []Counter{
+		{Level: "INFO", Logger: "", Value: 1},
+		{Level: "INFO", Logger: "api", Value: 2},
+		{Level: "ERROR", Logger: "api", Value: 1},
+	}, s.Entries)
+	assert.Equal(t, []Counter{{Level: "DEBUG", Logger: "", Value: 1}}, s.Dropped)
+	assert.Equal(t, []Counter{{Level: "WARN", Logger: "db", Value: 1}}, s.Errors)
+}
+
+func Test_Metrics_Handler(t *testing.T) {
+	t.Parallel()
+
+	m := newMetrics(t)
+	m.Written(log.LevelInfo, "quote\"\\\n")
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, PrometheusContentType, rec.Header().Get("Content-Type"))
+	assert.Equal(t, `# HELP log_entries_total Number of written log entries.
+# TYPE log_entries_total counter
+log_entries_total{level="INFO",logger=""} 1
+log_entries_total{level="INFO",logger="api"} 2
+log_entries_total{level="INFO",logger="quote\"\\\n"} 1
+log_entries_total{level="ERROR",logger="api"} 1
+# HELP log_dropped_total Number of log entries dropped by hooks.
+# TYPE log_dropped_total counter
+log_dropped_total{level="DEBUG",logger=""} 1
+# HELP log_errors_total Number of log entries failed to encode or write.
+# TYPE log_errors_total counter
+log_errors_total{level="WARN",logger="db"} 1
+`, rec.Body.String())
+}
+
+func Test_Metrics_Publish(t *testing.T) {
+	t.Parallel()
+
+	m := newMetrics(t)
+	m.Publish("log_metrics_test")
+
+	v := expvar.Get("log_metrics_test")
+	require.NotNil(t, v)
+
+	var s Snapshot
+	require.NoError(t, json.Unmarshal([]byte(v.String()), &s))
+	assert.Equal(t, m.Snapshot(), s)
+}
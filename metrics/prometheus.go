@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// PrometheusContentType
+// Тип содержимого текстового формата Prometheus
+const PrometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// family
+// Метрика в формате Prometheus
+type family struct {
+	name     string
+	help     string
+	counters []Counter
+}
+
+// WritePrometheus
+// Записывает метрики в текстовом формате Prometheus: log_entries_total, log_dropped_total и log_errors_total с
+// метками level и logger
+func (m *Metrics) WritePrometheus(w io.Writer) error {
+	s := m.Snapshot()
+
+	bw := bufio.NewWriter(w)
+
+	for _, f := range []family{
+		{name: "log_entries_total", help: "Number of written log entries.", counters: s.Entries},
+		{name: "log_dropped_total", help: "Number of log entries dropped by hooks.", counters: s.Dropped},
+		{name: "log_errors_total", help: "Number of log entries failed to encode or write.", counters: s.Errors},
+	} {
+		writeFamily(bw, f)
+	}
+
+	return bw.Flush()
+}
+
+// Handler
+// Возвращает http.Handler, отдающий метрики в текстовом формате Prometheus
+func (m *Metrics) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", PrometheusContentType)
+		_ = m.WritePrometheus(w)
+	})
+}
+
+func writeFamily(w *bufio.Writer, f family) {
+	_, _ = w.WriteString("# HELP " + f.name + " " + f.help + "\n")
+	_, _ = w.WriteString("# TYPE " + f.name + " counter\n")
+
+	for _, c := range f.counters {
+		_, _ = w.WriteString(f.name)
+		_, _ = w.WriteString(`{level="`)
+		_, _ = w.WriteString(escapeLabel(c.Level))
+		_, _ = w.WriteString(`",logger="`)
+		_, _ = w.WriteString(escapeLabel(c.Logger))
+		_, _ = w.WriteString(`"} `)
+		_, _ = w.WriteString(strconv.FormatUint(c.Value, 10))
+		_ = w.WriteByte('\n')
+	}
+}
+
+// _labelReplacer
+// Экранирование значений меток в текстовом формате Prometheus
+var _labelReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(s string) string {
+	return _labelReplacer.Replace(s)
+}
@@ -0,0 +1,89 @@
+package log
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Logger_Named(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		options []Option
+		key     string
+	}{
+		{
+			name: "default key",
+			key:  NameKey,
+		},
+		{
+			name:    "custom key",
+			options: []Option{WithNameKey("component")},
+			key:     "component",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			buf := &bytes.Buffer{}
+			l := NewLogger(append([]Option{WithFormat(FormatJSON), WithWriter(buf)}, tt.options...)...)
+
+			l.Info(NoContext, "unnamed")
+
+			api := l.Named("api")
+			api.Info(NoContext, "api")
+			api.Named("users").Named("").WithArgs(Int("n", 1)).Named("repo").Info(NoContext, "repo")
+
+			entries := decodeEntries(t, buf)
+			require.Len(t, entries, 3)
+
+			assert.NotContains(t, entries[0], tt.key)
+			assert.Equal(t, "api", entries[1][tt.key])
+			assert.Equal(t, "api.users.repo", entries[2][tt.key])
+			assert.InDelta(t, 1, entries[2]["n"], 0)
+		})
+	}
+}
+
+func Test_Logger_Named_LogFmt(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	NewLogger(WithFormat(FormatLogFmt), WithWriter(buf)).Named("api").Named("users").Info(NoContext, "message")
+
+	assert.Contains(t, buf.String(), "logger=api.users")
+}
+
+func Test_Logger_Named_WithOptions(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	l := NewLogger(WithWriter(&bytes.Buffer{})).Named("api").WithOptions(WithFormat(FormatJSON), WithWriter(buf))
+	l.Named("users").Info(NoContext, "message")
+
+	entries := decodeEntries(t, buf)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "api.users", entries[0][NameKey])
+}
+
+func Test_Logger_Named_DuplicateKeys(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	l := NewLogger(WithFormat(FormatJSON), WithWriter(buf), WithDuplicateKeys(DuplicateKeysLast))
+
+	l.Named("api").Info(NoContext, "named", String(NameKey, "user"))
+	l.Info(NoContext, "unnamed", String(NameKey, "user"))
+
+	entries := decodeEntries(t, buf)
+	require.Len(t, entries, 2)
+
+	assert.Equal(t, "api", entries[0][NameKey])
+	assert.Equal(t, "user", entries[1][NameKey])
+}
@@ -0,0 +1,63 @@
+package log
+
+import (
+	"time"
+
+	"github.com/anticrew/go-x/xio"
+)
+
+// param
+// Аргумент лога в виде ключа и значения для форматов, записываемых общим для драйверов энкодером
+type param struct {
+	key   string
+	value any
+}
+
+// paramsEncoder
+// Общий для драйверов энкодер формата, получающий аргументы лога в виде набора param в порядке добавления
+type paramsEncoder interface {
+	// encode
+	// Записывает лог в buf
+	encode(buf *xio.Buffer, level Level, t time.Time, msg string, params []param)
+}
+
+// newParamsEncoder
+// Возвращает энкодер для формата из опций
+func newParamsEncoder(opt Options) paramsEncoder {
+	switch opt.Format { //nolint:exhaustive // other formats are written by drivers
+	case FormatJournal:
+		return newJournalEncoder(opt)
+	case FormatGELF:
+		return newGELFEncoder(opt)
+	case FormatJSON:
+		return newSchemaEncoder(opt)
+	default:
+		return newSyslogEncoder(opt)
+	}
+}
+
+// encodesParams
+// Сообщает, что логи записываются общим для драйверов paramsEncoder: форматы syslog, FormatJournal, FormatGELF и
+// FormatJSON со схемой, отличной от SchemaDefault
+func (o *Options) encodesParams() bool {
+	switch o.Format {
+	case FormatSyslog5424, FormatSyslog3164, FormatJournal, FormatGELF:
+		return true
+	case FormatJSON:
+		return o.Schema != SchemaDefault
+	case FormatText, FormatLogFmt:
+	}
+
+	return false
+}
+
+// formatOptions
+// Дополняет опции требованиями формата: FormatJournal и FormatJSON со схемой получают источник в виде объекта для
+// записи в поля формата
+func formatOptions(opt Options) Options {
+	if opt.Format == FormatJournal || (opt.Format == FormatJSON && opt.Schema != SchemaDefault) {
+		opt.SourceFormat = SourceFormatObject
+	}
+
+	return opt
+}
@@ -0,0 +1,64 @@
+package log
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/anticrew/log/internal/caller"
+)
+
+// StackKey
+// Ключ для записи стека вызовов при панике
+const StackKey = "stack"
+
+// Recover
+// Перехватывает панику и записывает ERROR-лог с указанным сообщением, значением паники в виде ошибки ErrPanic, стеком
+// вызовов и аргументами, переданными в ctx, через Logger из ctx или Logger по умолчанию. Источником лога считается
+// место паники. Должна вызываться только с помощью defer: defer log.Recover(ctx, "worker")
+func Recover(ctx context.Context, msg string) {
+	if rec := recover(); rec != nil {
+		logPanic(ctx, msg, rec)
+	}
+}
+
+// RecoverAndPanic
+// Записывает лог, как Recover, после чего повторно вызывает панику с тем же значением. Должна вызываться только с
+// помощью defer: defer log.RecoverAndPanic(ctx, "worker")
+func RecoverAndPanic(ctx context.Context, msg string) {
+	if rec := recover(); rec != nil {
+		logPanic(ctx, msg, rec)
+		panic(rec)
+	}
+}
+
+// _goPanicMessage
+// Сообщение лога о панике в горутине, запущенной с помощью Go
+const _goPanicMessage = "goroutine panicked"
+
+// Go
+// Запускает fn в новой горутине, перехватывая панику с помощью Recover
+func Go(ctx context.Context, fn func(ctx context.Context)) {
+	go func() {
+		defer Recover(ctx, _goPanicMessage)
+
+		fn(ctx)
+	}()
+}
+
+// _panicSkip
+// Кол-во вызовов между logPanic и местом паники: Recover или RecoverAndPanic и runtime.gopanic
+const _panicSkip = 2
+
+// logPanic
+// Записывает лог о панике
+func logPanic(ctx context.Context, msg string, rec any) {
+	var err error
+	if e, ok := rec.(error); ok {
+		err = fmt.Errorf("%w: %w", ErrPanic, e)
+	} else {
+		err = fmt.Errorf("%w: %v", ErrPanic, rec)
+	}
+
+	l := AddCallerSkip(defaultLoggerFor(ctx), _panicSkip+1) // skip logPanic
+	l.Error(ctx, err, msg, String(StackKey, caller.Stack(_panicSkip+1)))
+}
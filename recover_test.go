@@ -0,0 +1,121 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func panicWorker(err error) {
+	panic(err) // recover_test.go:17
+}
+
+// syncBuffer
+// bytes.Buffer, безопасный для конкурентного использования
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
+
+func recoverLogger(t *testing.T) (context.Context, *syncBuffer) {
+	t.Helper()
+
+	buf := &syncBuffer{}
+	l := NewLogger(WithFormat(FormatJSON), WithWriter(buf), WithSource(""), WithSourceFormat(SourceFormatFileLine))
+
+	ctx := SetContextLogger(context.Background(), l)
+	ctx = AddContextArgs(ctx, String("job", "sync"))
+
+	return ctx, buf
+}
+
+func Test_Recover(t *testing.T) {
+	t.Parallel()
+
+	ctx, buf := recoverLogger(t)
+
+	func() {
+		defer Recover(ctx, "worker")
+
+		panicWorker(assert.AnError)
+	}()
+
+	entries := decodeEntries(t, bytes.NewBufferString(buf.String()))
+	require.Len(t, entries, 1)
+
+	e := entries[0]
+	assert.Equal(t, "worker", e[MessageKey])
+	assert.Equal(t, "ERROR", e[LevelKey])
+	assert.Equal(t, "sync", e["job"])
+	assert.Equal(t, "panic: "+assert.AnError.Error(), e[ErrorKey])
+	_, file, _, _ := runtime.Caller(0)
+	assert.Equal(t, shortPath(file)+":17", e[SourceKey])
+
+	stack, ok := e[StackKey].(string)
+	require.True(t, ok)
+	assert.True(t, strings.HasPrefix(stack, "github.com/anticrew/log.panicWorker\n"), stack)
+}
+
+func Test_RecoverAndPanic(t *testing.T) {
+	t.Parallel()
+
+	ctx, buf := recoverLogger(t)
+
+	assert.PanicsWithValue(t, "boom", func() {
+		defer RecoverAndPanic(ctx, "worker")
+
+		panic("boom")
+	})
+
+	entries := decodeEntries(t, bytes.NewBufferString(buf.String()))
+	require.Len(t, entries, 1)
+	assert.Equal(t, "panic: boom", entries[0][ErrorKey])
+
+	assert.NotPanics(t, func() {
+		defer RecoverAndPanic(ctx, "no panic")
+	})
+	assert.NotContains(t, buf.String(), "no panic")
+}
+
+func Test_Go(t *testing.T) {
+	t.Parallel()
+
+	ctx, buf := recoverLogger(t)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	Go(ctx, func(ctx context.Context) {
+		defer wg.Done() // runs before Recover
+
+		assert.Equal(t, []Arg{String("job", "sync")}, GetContextArgs(ctx))
+		panic("boom")
+	})
+
+	wg.Wait()
+
+	require.Eventually(t, func() bool {
+		return strings.Contains(buf.String(), _goPanicMessage)
+	}, time.Second, time.Millisecond)
+}
@@ -0,0 +1,311 @@
+package log
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anticrew/go-x/xio"
+)
+
+// Schema
+// Схема полей лога: ключи временной метки, уровня, сообщения, источника, ошибки и имени Logger, а также
+// наименования уровней, принятые в системе сбора логов. Структура схемы (вложенные поля, числовая важность, разбиение
+// источника) применяется к формату FormatJSON, в остальных форматах используются только ключи
+type Schema uint
+
+const (
+	// SchemaDefault
+	// Ключи по умолчанию: LevelKey, TimeKey, MessageKey, SourceKey, ErrorKey и NameKey
+	SchemaDefault Schema = iota
+
+	// SchemaECS
+	// Elastic Common Schema: @timestamp, log.level, message, error.message, log.logger и ecs.version, источник
+	// записывается в log.origin.file.name, log.origin.file.line и log.origin.function. Уровни записываются в нижнем
+	// регистре
+	SchemaECS
+
+	// SchemaOTel
+	// Модель данных логов OpenTelemetry: Timestamp, SeverityText, SeverityNumber и Body, аргументы записываются в
+	// объект Attributes, ошибка - в атрибут exception.message, источник - в атрибуты code.file.path, code.line.number и
+	// code.function.name, имя Logger - в InstrumentationScope.Name, аргументы TraceIDKey и SpanIDKey - в TraceId и
+	// SpanId
+	SchemaOTel
+
+	// SchemaGCP
+	// Структурированные логи Google Cloud Logging: timestamp, severity, message и error, источник записывается в
+	// объект logging.googleapis.com/sourceLocation. Уровни записываются наименованиями LogSeverity: DEBUG, INFO,
+	// NOTICE, WARNING, ERROR, CRITICAL
+	SchemaGCP
+
+	// SchemaDatadog
+	// Стандартные атрибуты Datadog: timestamp, status, message, error.message и logger.name, источник записывается в
+	// logger.file_name, logger.line и logger.method_name. Уровни записываются статусами: debug, info, notice, warning,
+	// error, critical
+	SchemaDatadog
+)
+
+func (s Schema) String() string {
+	switch s {
+	case SchemaDefault:
+		return "default"
+	case SchemaECS:
+		return "ecs"
+	case SchemaOTel:
+		return "otel"
+	case SchemaGCP:
+		return "gcp"
+	case SchemaDatadog:
+		return "datadog"
+	default:
+		return fmt.Sprintf("Schema<%d>", s)
+	}
+}
+
+func (s Schema) IsValid() bool {
+	return s >= SchemaDefault && s <= SchemaDatadog
+}
+
+const (
+	// TraceIDKey
+	// Ключ аргумента с идентификатором трассировки в шестнадцатеричном виде, SchemaOTel записывает его в поле TraceId.
+	// Обычно добавляется в context.Context с помощью AddContextArgs
+	TraceIDKey = "trace_id"
+
+	// SpanIDKey
+	// Ключ аргумента с идентификатором спана в шестнадцатеричном виде, SchemaOTel записывает его в поле SpanId
+	SpanIDKey = "span_id"
+)
+
+// schemaKeys
+// Ключи и формат временной метки схемы
+type schemaKeys struct {
+	time, timeFormat, level, message, source, err, name string
+}
+
+// _ecsVersion
+// Версия ECS, записываемая в ecs.version
+const _ecsVersion = "1.6.0"
+
+// _schemaKeys
+// Ключи схем, индекс соответствует Schema
+var _schemaKeys = [...]schemaKeys{
+	SchemaDefault: {TimeKey, time.RFC3339, LevelKey, MessageKey, SourceKey, ErrorKey, NameKey},
+	SchemaECS: {"@timestamp", "2006-01-02T15:04:05.000Z07:00", "log.level", "message", "log.origin", "error.message",
+		"log.logger"},
+	SchemaOTel:    {"Timestamp", time.RFC3339Nano, "SeverityText", "Body", "code", "exception.message", "InstrumentationScope"},
+	SchemaGCP:     {"timestamp", time.RFC3339Nano, "severity", "message", "logging.googleapis.com/sourceLocation", ErrorKey, NameKey},
+	SchemaDatadog: {"timestamp", "2006-01-02T15:04:05.000Z07:00", "status", "message", "logger", "error.message", "logger.name"},
+}
+
+// WithSchema
+// Устанавливает ключи временной метки, уровня, сообщения, источника, ошибки и имени Logger, а также формат временной
+// метки согласно схеме. Опции, указанные после WithSchema, переопределяют отдельные ключи. Источник записывается только
+// при включении с помощью WithSource, ключ источника для FormatJSON определяется схемой
+func WithSchema(schema Schema) Option {
+	if !schema.IsValid() {
+		return emptyOption
+	}
+
+	keys := _schemaKeys[schema]
+
+	return func(o Options) Options {
+		o.Schema = schema
+		o.TimeKey = keys.time
+		o.TimeFormat = keys.timeFormat
+		o.LevelKey = keys.level
+		o.MessageKey = keys.message
+		o.SourceKey = keys.source
+		o.ErrorKey = keys.err
+		o.NameKey = keys.name
+		return o
+	}
+}
+
+// schemaEncoder
+// Общий для драйверов энкодер формата FormatJSON со схемой, отличной от SchemaDefault
+type schemaEncoder struct {
+	schema Schema
+
+	timeKey    string
+	timeFormat string
+	levelKey   string
+	levelNames map[Level]string
+	messageKey string
+	sourceKey  string
+	nameKey    string
+}
+
+func newSchemaEncoder(opt Options) *schemaEncoder {
+	return &schemaEncoder{
+		schema:     opt.Schema,
+		timeKey:    opt.TimeKey,
+		timeFormat: opt.TimeFormat,
+		levelKey:   opt.LevelKey,
+		levelNames: opt.LevelNames,
+		messageKey: opt.MessageKey,
+		sourceKey:  opt.SourceKey,
+		nameKey:    opt.NameKey,
+	}
+}
+
+// encode
+// Записывает лог в buf в виде объекта JSON с переводом строки в конце
+func (e *schemaEncoder) encode(buf *xio.Buffer, level Level, t time.Time, msg string, params []param) {
+	buf.WriteByte('{')
+
+	if len(e.timeKey) > 0 {
+		writeJSONString(buf, e.timeKey)
+		buf.WriteByte(':')
+		writeJSONString(buf, t.Format(e.timeFormat))
+		buf.WriteByte(',')
+	}
+
+	if len(e.levelKey) > 0 {
+		writeJSONString(buf, e.levelKey)
+		buf.WriteByte(':')
+		writeJSONString(buf, e.levelName(level))
+		buf.WriteByte(',')
+	}
+
+	if e.schema == SchemaOTel {
+		buf.WriteString(`"SeverityNumber":`).WriteInt64(int64(otelSeverity(level))).WriteByte(',')
+	}
+
+	messageKey := e.messageKey
+	if len(messageKey) == 0 {
+		messageKey = MessageKey
+	}
+
+	writeJSONString(buf, messageKey)
+	buf.WriteByte(':')
+	writeJSONString(buf, msg)
+
+	if e.schema == SchemaECS {
+		buf.WriteString(`,"ecs.version":"` + _ecsVersion + `"`)
+	}
+
+	if e.schema == SchemaOTel {
+		buf.WriteString(`,"Attributes":{`)
+	}
+
+	// поля верхнего уровня SchemaOTel, записываемые после Attributes
+	var name, traceID, spanID *param
+
+	first := e.schema == SchemaOTel
+
+	for i, p := range params {
+		if e.schema == SchemaOTel {
+			switch p.key {
+			case e.nameKey:
+				name = &params[i]
+				continue
+			case TraceIDKey:
+				traceID = &params[i]
+				continue
+			case SpanIDKey:
+				spanID = &params[i]
+				continue
+			}
+		}
+
+		if !first {
+			buf.WriteByte(',')
+		}
+
+		first = false
+
+		if source, ok := p.value.(map[string]any); ok && p.key == e.sourceKey {
+			e.writeSource(buf, source)
+			continue
+		}
+
+		writeJSONString(buf, p.key)
+		buf.WriteByte(':')
+		writeJSONValue(buf, p.value)
+	}
+
+	if e.schema == SchemaOTel {
+		buf.WriteByte('}')
+	}
+
+	if traceID != nil {
+		buf.WriteString(`,"TraceId":`)
+		writeJSONString(buf, syslogValue(traceID.value))
+	}
+
+	if spanID != nil {
+		buf.WriteString(`,"SpanId":`)
+		writeJSONString(buf, syslogValue(spanID.value))
+	}
+
+	if name != nil {
+		buf.WriteString(`,"InstrumentationScope":{"Name":`)
+		writeJSONValue(buf, name.value)
+		buf.WriteByte('}')
+	}
+
+	buf.WriteString("}\n")
+}
+
+// writeSource
+// Записывает источник, полученный в формате SourceFormatObject, в поля схемы
+func (e *schemaEncoder) writeSource(buf *xio.Buffer, source map[string]any) {
+	file, line, function := source[SourceFileKey], source[SourceLineKey], source[SourceFunctionKey]
+
+	switch e.schema {
+	case SchemaECS:
+		writeJSONFields(buf, "log.origin.file.name", file, "log.origin.file.line", line, "log.origin.function", function)
+	case SchemaOTel:
+		writeJSONFields(buf, "code.file.path", file, "code.line.number", line, "code.function.name", function)
+	case SchemaGCP:
+		// LogEntrySourceLocation.line - int64, который в JSON записывается строкой
+		buf.WriteString(`"logging.googleapis.com/sourceLocation":{`)
+		writeJSONFields(buf, "file", file, "line", syslogValue(line), "function", function)
+		buf.WriteByte('}')
+	case SchemaDatadog:
+		writeJSONFields(buf, "logger.file_name", file, "logger.line", line, "logger.method_name", function)
+	case SchemaDefault:
+		writeJSONString(buf, e.sourceKey)
+		buf.WriteByte(':')
+		writeJSONValue(buf, source)
+	}
+}
+
+// levelName
+// Возвращает наименование уровня для схемы. Наименования из WithLevelNames имеют приоритет
+func (e *schemaEncoder) levelName(level Level) string {
+	if name, ok := e.levelNames[level]; ok {
+		return name
+	}
+
+	switch e.schema {
+	case SchemaECS:
+		return strings.ToLower(formatLevel(level, nil))
+	case SchemaGCP:
+		return _gcpSeverities[LevelSeverity(level)]
+	case SchemaDatadog:
+		return _datadogStatuses[LevelSeverity(level)]
+	case SchemaDefault, SchemaOTel:
+	}
+
+	return formatLevel(level, nil)
+}
+
+var (
+	// _gcpSeverities
+	// Наименования LogSeverity Google Cloud Logging, индекс соответствует Severity
+	_gcpSeverities = [...]string{"EMERGENCY", "ALERT", "CRITICAL", "ERROR", "WARNING", "NOTICE", "INFO", "DEBUG"}
+
+	// _datadogStatuses
+	// Статусы Datadog, индекс соответствует Severity
+	_datadogStatuses = [...]string{"emerg", "alert", "critical", "error", "warning", "notice", "info", "debug"}
+)
+
+// otelSeverity
+// Возвращает SeverityNumber OpenTelemetry для уровня: LevelTrace - 1 (TRACE), LevelDebug - 5 (DEBUG), LevelInfo -
+// 9 (INFO), LevelWarn - 13 (WARN), LevelError - 17 (ERROR), промежуточные уровни - промежуточные значения в
+// диапазоне 1-24
+func otelSeverity(level Level) int {
+	return min(max(int(level-LevelTrace)+1, 1), 24)
+}
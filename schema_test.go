@@ -0,0 +1,211 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anticrew/go-x/xio"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// decodeSchema
+// Записывает ERROR-лог с ошибкой, аргументами, именем и источником и возвращает разобранный JSON без временной метки
+func decodeSchema(t *testing.T, schema Schema, timeKey string, options ...Option) map[string]any {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+	l := NewLogger(append([]Option{WithFormat(FormatJSON), WithWriter(buf), WithSchema(schema), WithSource("")},
+		options...)...)
+
+	l.Named("svc").WithArgs(Int("n", 1)).Error(NoContext, errors.New("failed"), "hello",
+		Any("m", map[string]int{"k": 1}), String(TraceIDKey, traceID))
+
+	var m map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &m), buf.String())
+
+	_, ok := m[timeKey].(string)
+	require.True(t, ok, buf.String())
+	delete(m, timeKey)
+
+	return m
+}
+
+// traceID
+// Идентификатор трассировки, добавляемый в лог decodeSchema
+const traceID = "0af7651916cd43dd8448eb211c80319c"
+
+// sourceLine
+// Строка вызова Error в decodeSchema
+const sourceLine = 27
+
+func Test_WithSchema_ECS(t *testing.T) {
+	t.Parallel()
+
+	m := decodeSchema(t, SchemaECS, "@timestamp")
+
+	file, ok := m["log.origin.file.name"].(string)
+	require.True(t, ok)
+	assert.True(t, strings.HasSuffix(file, "/schema_test.go"), file)
+	delete(m, "log.origin.file.name")
+
+	assert.Equal(t, map[string]any{
+		"log.level":            "error",
+		"message":              "hello",
+		"ecs.version":          _ecsVersion,
+		"n":                    float64(1),
+		"m":                    map[string]any{"k": float64(1)},
+		"error.message":        "failed",
+		"log.logger":           "svc",
+		"log.origin.file.line": float64(sourceLine),
+		"log.origin.function":  "github.com/anticrew/log.decodeSchema",
+		TraceIDKey:             traceID,
+	}, m)
+}
+
+func Test_WithSchema_OTel(t *testing.T) {
+	t.Parallel()
+
+	m := decodeSchema(t, SchemaOTel, "Timestamp")
+
+	attrs, ok := m["Attributes"].(map[string]any)
+	require.True(t, ok)
+	delete(attrs, "code.file.path")
+
+	assert.Equal(t, map[string]any{
+		"SeverityText":   "ERROR",
+		"SeverityNumber": float64(17),
+		"Body":           "hello",
+		"Attributes": map[string]any{
+			"n":                  float64(1),
+			"m":                  map[string]any{"k": float64(1)},
+			"exception.message":  "failed",
+			"code.line.number":   float64(sourceLine),
+			"code.function.name": "github.com/anticrew/log.decodeSchema",
+		},
+		"TraceId":              traceID,
+		"InstrumentationScope": map[string]any{"Name": "svc"},
+	}, m)
+}
+
+func Test_WithSchema_GCP(t *testing.T) {
+	t.Parallel()
+
+	m := decodeSchema(t, SchemaGCP, "timestamp")
+
+	source, ok := m["logging.googleapis.com/sourceLocation"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, strconv.Itoa(sourceLine), source["line"])
+	assert.Equal(t, "github.com/anticrew/log.decodeSchema", source["function"])
+	delete(m, "logging.googleapis.com/sourceLocation")
+
+	assert.Equal(t, map[string]any{
+		"severity": "ERROR",
+		"message":  "hello",
+		"n":        float64(1),
+		"m":        map[string]any{"k": float64(1)},
+		"error":    "failed",
+		"logger":   "svc",
+		TraceIDKey: traceID,
+	}, m)
+}
+
+func Test_WithSchema_Datadog(t *testing.T) {
+	t.Parallel()
+
+	m := decodeSchema(t, SchemaDatadog, "ts", WithTime("ts", ""), WithLevelNames(map[Level]string{LevelError: "ERR"}))
+
+	delete(m, "logger.file_name")
+
+	assert.Equal(t, map[string]any{
+		"status":             "ERR",
+		"message":            "hello",
+		"n":                  float64(1),
+		"m":                  map[string]any{"k": float64(1)},
+		"error.message":      "failed",
+		"logger.name":        "svc",
+		"logger.line":        float64(sourceLine),
+		"logger.method_name": "github.com/anticrew/log.decodeSchema",
+		TraceIDKey:           traceID,
+	}, m)
+}
+
+func Test_WithSchema_Text(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	l := NewLogger(WithFormat(FormatLogFmt), WithWriter(buf), WithSchema(SchemaECS))
+
+	l.Warn(NoContext, errors.New("failed"), "hello")
+
+	assert.Contains(t, buf.String(), "log.level=WARN")
+	assert.Contains(t, buf.String(), "message=hello")
+	assert.Contains(t, buf.String(), "error.message=failed")
+}
+
+func Test_SchemaLevels(t *testing.T) {
+	t.Parallel()
+
+	for level, want := range map[Level][4]string{
+		LevelTrace:     {"trace", "DEBUG", "debug", "1"},
+		LevelDebug:     {"debug", "DEBUG", "debug", "5"},
+		LevelInfo:      {"info", "INFO", "info", "9"},
+		LevelInfo + 2:  {"info+2", "NOTICE", "notice", "11"},
+		LevelWarn:      {"warn", "WARNING", "warning", "13"},
+		LevelError:     {"error", "ERROR", "error", "17"},
+		LevelError + 4: {"error+4", "CRITICAL", "critical", "21"},
+		LevelError + 9: {"error+9", "CRITICAL", "critical", "24"},
+	} {
+		assert.Equal(t, want[0], (&schemaEncoder{schema: SchemaECS}).levelName(level))
+		assert.Equal(t, want[1], (&schemaEncoder{schema: SchemaGCP}).levelName(level))
+		assert.Equal(t, want[2], (&schemaEncoder{schema: SchemaDatadog}).levelName(level))
+		assert.Equal(t, want[3], syslogValue(otelSeverity(level)))
+	}
+}
+
+func Test_WithErrorKey(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	l := NewLogger(WithFormat(FormatJSON), WithWriter(buf), WithErrorKey("err"))
+
+	l.Error(NoContext, errors.New("failed"), "hello")
+
+	var m map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &m))
+	assert.Equal(t, "failed", m["err"])
+	assert.NotContains(t, m, ErrorKey)
+}
+
+func Test_writeJSONValue(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		value any
+		want  string
+	}{
+		{int64(1), `1`},
+		{uint8(2), `2`},
+		{1.0, `1.0`},
+		{float32(1.5), `1.5`},
+		{1e21, `1000000000000000000000.0`},
+		{math.Inf(1), `"+Inf"`},
+		{true, `true`},
+		{nil, `null`},
+		{time.Second, `"1s"`},
+		{[]int{1, 2}, `[1,2]`},
+		{errors.New(`a"b`), `"a\"b"`},
+	} {
+		buf := xio.NewBuffer()
+		writeJSONValue(buf, tc.value)
+
+		assert.Equal(t, tc.want, buf.String())
+		buf.Dispose()
+	}
+}
@@ -0,0 +1,100 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// Backoff
+// Политика повторных попыток с экспоненциальной задержкой: после каждой неудачной попытки задержка удваивается, но не
+// превышает Max. Случайное отклонение Jitter разносит повторные попытки разных процессов во времени
+type Backoff struct {
+	// Attempts
+	// Максимальное кол-во попыток, включая первую
+	Attempts int
+
+	// Initial
+	// Задержка перед второй попыткой
+	Initial time.Duration
+
+	// Max
+	// Максимальная задержка между попытками
+	Max time.Duration
+
+	// Jitter
+	// Доля случайного отклонения задержки от 0 до 1: при 0.2 задержка выбирается в диапазоне ±20%
+	Jitter float64
+}
+
+// DefaultBackoff
+// Политика повторных попыток по умолчанию: 5 попыток с задержкой от 1 до 30 секунд и отклонением ±20%
+var DefaultBackoff = Backoff{
+	Attempts: 5,
+	Initial:  time.Second,
+	Max:      30 * time.Second,
+	Jitter:   0.2,
+}
+
+// retryableError
+// Ошибка, после которой допустима повторная попытка. Задержка after, полученная от сервера, заменяет задержку Backoff
+type retryableError struct {
+	err   error
+	after time.Duration
+}
+
+func (e *retryableError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryableError) Unwrap() error {
+	return e.err
+}
+
+// do
+// Вызывает fn до успешного выполнения, ошибки, отличной от retryableError, исчерпания попыток или отмены ctx.
+// Возвращает последнюю ошибку fn
+func (b Backoff) do(ctx context.Context, fn func() error) error {
+	delay := b.Initial
+
+	for attempt := 1; ; attempt++ {
+		err := fn()
+
+		var re *retryableError
+		if !errors.As(err, &re) {
+			return err
+		}
+
+		if attempt >= b.Attempts {
+			return re.err
+		}
+
+		wait := b.jitter(delay)
+		if re.after > 0 {
+			wait = re.after
+		}
+
+		timer := time.NewTimer(min(wait, b.Max))
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w: %w", re.err, context.Cause(ctx))
+		}
+
+		delay = min(delay*2, b.Max)
+	}
+}
+
+// jitter
+// Возвращает задержку со случайным отклонением в пределах Jitter
+func (b Backoff) jitter(d time.Duration) time.Duration {
+	if b.Jitter <= 0 {
+		return d
+	}
+
+	return time.Duration(float64(d) * (1 + min(b.Jitter, 1)*(2*rand.Float64()-1))) //nolint:gosec // not secure
+}
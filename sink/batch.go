@@ -0,0 +1,160 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+)
+
+// batcher
+// Накапливает элементы и передает их в export пакетами не более size элементов: при накоплении size элементов, по
+// истечении interval и при закрытии. Export выполняется последовательно в отдельной горутине, пока выполняется
+// export, элементы продолжают накапливаться, но не более queue. Если указан idle, он вызывается в той же горутине
+// по истечении interval, когда накопленных элементов нет, и при закрытии после передачи накопленных элементов.
+// Context.Context, переданный в export и idle, отменяется, если закрытие не завершилось за отведенное время
+type batcher[T any] struct {
+	size   int
+	queue  int
+	export func(ctx context.Context, items []T)
+	idle   func(ctx context.Context)
+
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+
+	mu     sync.Mutex
+	items  []T
+	closed bool
+
+	flush   chan struct{}
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+func newBatcher[T any](
+	size, queue int, interval time.Duration, export func(ctx context.Context, items []T), idle func(ctx context.Context),
+) *batcher[T] {
+	ctx, cancel := context.WithCancelCause(context.Background())
+
+	b := &batcher[T]{
+		size:    size,
+		queue:   max(queue, size),
+		export:  export,
+		idle:    idle,
+		ctx:     ctx,
+		cancel:  cancel,
+		flush:   make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+
+	go b.run(interval)
+
+	return b
+}
+
+// add
+// Добавляет элемент в очередь. Возвращает ErrQueueFull, если очередь заполнена, и ErrClosed после close
+func (b *batcher[T]) add(item T) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return ErrClosed
+	}
+
+	if len(b.items) >= b.queue {
+		return ErrQueueFull
+	}
+
+	b.items = append(b.items, item)
+
+	if len(b.items) >= b.size {
+		select {
+		case b.flush <- struct{}{}:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// close
+// Передает в export накопленные элементы и останавливает горутину, последующие вызовы add возвращают ErrClosed. Если
+// за timeout передача не завершилась, отменяет context.Context, переданный в export и idle, дожидается остановки
+// горутины и возвращает ErrCloseTimeout. Нулевое значение timeout отключает ограничение
+func (b *batcher[T]) close(timeout time.Duration) error {
+	b.mu.Lock()
+	closed := b.closed
+	b.closed = true
+	b.mu.Unlock()
+
+	if !closed {
+		close(b.done)
+	}
+
+	if timeout > 0 {
+		timer := time.AfterFunc(timeout, func() {
+			b.cancel(ErrCloseTimeout)
+		})
+
+		defer timer.Stop()
+	}
+
+	<-b.stopped
+
+	if errors.Is(context.Cause(b.ctx), ErrCloseTimeout) {
+		return ErrCloseTimeout
+	}
+
+	return nil
+}
+
+func (b *batcher[T]) run(interval time.Duration) {
+	defer close(b.stopped)
+	defer b.cancel(ErrClosed)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if !b.drain() && b.idle != nil {
+				b.idle(b.ctx)
+			}
+		case <-b.flush:
+			b.drain()
+		case <-b.done:
+			b.drain()
+
+			if b.idle != nil {
+				b.idle(b.ctx)
+			}
+
+			return
+		}
+	}
+}
+
+// drain
+// Передает в export все накопленные элементы пакетами не более size элементов. Сообщает, были ли переданы элементы
+func (b *batcher[T]) drain() bool {
+	var drained bool
+
+	for {
+		b.mu.Lock()
+		n := min(len(b.items), b.size)
+		items := slices.Clone(b.items[:n])
+		b.items = append(b.items[:0], b.items[n:]...)
+		b.mu.Unlock()
+
+		if n == 0 {
+			return drained
+		}
+
+		b.export(b.ctx, items)
+		drained = true
+	}
+}
@@ -0,0 +1,261 @@
+package sink
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Record
+// Лог, записанный в HTTP
+type Record struct {
+	// Time
+	// Время записи лога
+	Time time.Time
+
+	// Data
+	// Лог без завершающего перевода строки
+	Data []byte
+}
+
+// Encoder
+// Формирует тело запроса HTTP из пакета логов
+type Encoder interface {
+	// ContentType
+	// Возвращает значение заголовка Content-Type
+	ContentType() string
+
+	// Encode
+	// Добавляет к dst тело запроса для пакета логов
+	Encode(dst []byte, records []Record) ([]byte, error)
+}
+
+// ResponseChecker
+// Реализуется Encoder, сервер которого сообщает об ошибках отдельных логов в теле успешного ответа, например,
+// ElasticsearchEncoder. HTTP вызывает CheckResponse для каждого ответа со статусом 2xx
+type ResponseChecker interface {
+	// CheckResponse
+	// Проверяет ответ сервера resp на запрос с телом body. Возвращает тело запроса с логами, которые допустимо
+	// отправить повторно, и ошибку для логов, отклоненных сервером без возможности повтора
+	CheckResponse(body, resp []byte) ([]byte, error)
+}
+
+// LokiEncoder
+// Encoder запроса push Grafana Loki (/loki/api/v1/push) в кодировке JSON. Логи группируются в потоки по меткам:
+// постоянным и полученным из аргументов лога в формате log.FormatJSON. Временной меткой считается время записи лога
+type LokiEncoder struct {
+	labels map[string]string
+	keys   []string
+}
+
+// NewLokiEncoder
+// Создает LokiEncoder с постоянными метками labels и метками из значений аргументов верхнего уровня с ключами keys.
+// Недопустимые символы названий меток заменяются на '_': аргумент "log.level" становится меткой log_level
+func NewLokiEncoder(labels map[string]string, keys ...string) *LokiEncoder {
+	e := &LokiEncoder{
+		labels: make(map[string]string, len(labels)),
+		keys:   slices.Clone(keys),
+	}
+
+	for k, v := range labels {
+		e.labels[lokiLabelName(k)] = v
+	}
+
+	return e
+}
+
+func (e *LokiEncoder) ContentType() string {
+	return "application/json"
+}
+
+// lokiStream
+// Поток логов с общим набором меток
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+func (e *LokiEncoder) Encode(dst []byte, records []Record) ([]byte, error) {
+	var streams []*lokiStream
+
+	index := make(map[string]*lokiStream)
+	for _, r := range records {
+		labels := e.recordLabels(r.Data)
+
+		key := lokiStreamKey(labels)
+		stream, ok := index[key]
+		if !ok {
+			stream = &lokiStream{Stream: labels}
+			index[key] = stream
+			streams = append(streams, stream)
+		}
+
+		stream.Values = append(stream.Values, [2]string{strconv.FormatInt(r.Time.UnixNano(), 10), string(r.Data)})
+	}
+
+	body, err := json.Marshal(struct {
+		Streams []*lokiStream `json:"streams"`
+	}{streams})
+	if err != nil {
+		return dst, err
+	}
+
+	return append(dst, body...), nil
+}
+
+// recordLabels
+// Возвращает метки лога: постоянные и значения аргументов с ключами keys
+func (e *LokiEncoder) recordLabels(data []byte) map[string]string {
+	labels := maps.Clone(e.labels)
+	if len(e.keys) == 0 {
+		return labels
+	}
+
+	var args map[string]json.RawMessage
+	if err := json.Unmarshal(data, &args); err != nil {
+		return labels
+	}
+
+	for _, key := range e.keys {
+		raw, ok := args[key]
+		if !ok {
+			continue
+		}
+
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			value = string(raw)
+		}
+
+		labels[lokiLabelName(key)] = value
+	}
+
+	return labels
+}
+
+// lokiStreamKey
+// Возвращает ключ набора меток для группировки логов в потоки
+func lokiStreamKey(labels map[string]string) string {
+	var sb strings.Builder
+
+	for _, k := range slices.Sorted(maps.Keys(labels)) {
+		sb.WriteString(k)
+		sb.WriteByte('=')
+		sb.WriteString(strconv.Quote(labels[k]))
+		sb.WriteByte(',')
+	}
+
+	return sb.String()
+}
+
+// lokiLabelName
+// Возвращает допустимое название метки Loki: [a-zA-Z_][a-zA-Z0-9_]*
+func lokiLabelName(key string) string {
+	name := []byte(key)
+	for i, c := range name {
+		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && c != '_' && (c < '0' || c > '9' || i == 0) {
+			name[i] = '_'
+		}
+	}
+
+	if len(name) == 0 {
+		return "_"
+	}
+
+	return string(name)
+}
+
+// ElasticsearchEncoder
+// Encoder запроса Elasticsearch _bulk в формате NDJSON: каждый лог в формате log.FormatJSON добавляется в индекс или
+// поток данных операцией create. Реализует ResponseChecker: логи, отклоненные со статусом 429, 502, 503 или 504,
+// отправляются повторно, остальные ошибки операций передаются в ErrorHandler
+type ElasticsearchEncoder struct {
+	action []byte
+}
+
+// NewElasticsearchEncoder
+// Создает ElasticsearchEncoder, добавляющий логи в индекс или поток данных index
+func NewElasticsearchEncoder(index string) *ElasticsearchEncoder {
+	name, _ := json.Marshal(index) //nolint:errchkjson // string is always encoded
+
+	return &ElasticsearchEncoder{
+		action: []byte(`{"create":{"_index":` + string(name) + "}}\n"),
+	}
+}
+
+func (e *ElasticsearchEncoder) ContentType() string {
+	return "application/x-ndjson"
+}
+
+func (e *ElasticsearchEncoder) Encode(dst []byte, records []Record) ([]byte, error) {
+	for _, r := range records {
+		dst = append(dst, e.action...)
+		dst = append(dst, bytes.TrimSpace(r.Data)...)
+		dst = append(dst, '\n')
+	}
+
+	return dst, nil
+}
+
+// esBulkResponse
+// Ответ Elasticsearch на запрос _bulk: результаты операций в порядке запроса
+type esBulkResponse struct {
+	Errors bool                      `json:"errors"`
+	Items  []map[string]esBulkResult `json:"items"`
+}
+
+// esBulkResult
+// Результат операции запроса _bulk
+type esBulkResult struct {
+	Status int `json:"status"`
+	Error  struct {
+		Type   string `json:"type"`
+		Reason string `json:"reason"`
+	} `json:"error"`
+}
+
+func (e *ElasticsearchEncoder) CheckResponse(body, resp []byte) ([]byte, error) {
+	// пустой ответ не содержит результатов операций, например, при отправке через прокси
+	if len(bytes.TrimSpace(resp)) == 0 {
+		return nil, nil
+	}
+
+	var bulk esBulkResponse
+	if err := json.Unmarshal(resp, &bulk); err != nil {
+		return nil, fmt.Errorf("decode bulk response: %w", err)
+	}
+
+	if !bulk.Errors {
+		return nil, nil
+	}
+
+	// каждая операция занимает две строки: действие и лог
+	lines := bytes.SplitAfter(body, []byte{'\n'})
+
+	var (
+		retry []byte
+		errs  []error
+	)
+
+	for i, item := range bulk.Items {
+		for _, result := range item {
+			switch {
+			case result.Status >= 200 && result.Status < 300:
+			case retryableStatus(result.Status) && 2*i+1 < len(lines):
+				retry = append(retry, lines[2*i]...)
+				retry = append(retry, lines[2*i+1]...)
+			default:
+				errs = append(errs, fmt.Errorf("%w: item %d: status %d: %s: %s",
+					ErrPartialFailure, i, result.Status, result.Error.Type, result.Error.Reason))
+			}
+		}
+	}
+
+	return retry, errors.Join(errs...)
+}
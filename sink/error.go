@@ -0,0 +1,33 @@
+package sink
+
+import "errors"
+
+var (
+	// ErrUnsupportedNetwork
+	// Ошибка создания Sink для неподдерживаемого типа сети
+	ErrUnsupportedNetwork = errors.New("unsupported network")
+
+	// ErrClosed
+	// Ошибка записи в закрытый Sink
+	ErrClosed = errors.New("sink closed")
+
+	// ErrMessageTooLarge
+	// Ошибка записи сообщения, превышающего допустимый размер
+	ErrMessageTooLarge = errors.New("message too large")
+
+	// ErrQueueFull
+	// Ошибка записи в Sink, очередь которого заполнена, например, пока сервер недоступен
+	ErrQueueFull = errors.New("sink queue full")
+
+	// ErrUnexpectedStatus
+	// Ошибка отправки, сервер ответил неуспешным статусом HTTP
+	ErrUnexpectedStatus = errors.New("unexpected status")
+
+	// ErrPartialFailure
+	// Ошибка отправки, сервер принял пакет, но отклонил часть логов в нем
+	ErrPartialFailure = errors.New("partial failure")
+
+	// ErrCloseTimeout
+	// Ошибка закрытия Sink, не успевшего отправить накопленные логи за отведенное время
+	ErrCloseTimeout = errors.New("sink close timeout")
+)
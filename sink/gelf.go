@@ -0,0 +1,324 @@
+package sink
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"sync"
+	"time"
+)
+
+// Compression
+// Способ сжатия сообщений GELF, передаваемых по UDP
+type Compression int
+
+const (
+	// CompressionNone
+	// Сообщения передаются без сжатия
+	CompressionNone Compression = iota
+
+	// CompressionGzip
+	// Сообщения сжимаются gzip
+	CompressionGzip
+
+	// CompressionZlib
+	// Сообщения сжимаются zlib
+	CompressionZlib
+)
+
+const (
+	// DefaultGELFChunkSize
+	// Размер датаграммы GELF по умолчанию, включая заголовок части, подходит для передачи через интернет
+	DefaultGELFChunkSize = 1420
+
+	// _gelfChunkHeaderSize
+	// Размер заголовка части: magic (2 байта), идентификатор сообщения (8 байт), номер части и количество частей
+	_gelfChunkHeaderSize = 12
+
+	// _gelfMaxChunks
+	// Максимальное количество частей сообщения
+	_gelfMaxChunks = 128
+)
+
+// _gelfChunkMagic
+// Магические байты заголовка части сообщения GELF
+var _gelfChunkMagic = [2]byte{0x1e, 0x0f}
+
+// GELFOptions
+// Настройки GELF
+type GELFOptions struct {
+	// Compression
+	// Способ сжатия сообщений, передаваемых по UDP, по умолчанию - CompressionNone. По TCP сообщения передаются без
+	// сжатия
+	Compression Compression
+
+	// ChunkSize
+	// Максимальный размер датаграммы UDP, включая заголовок части, по умолчанию - DefaultGELFChunkSize. Сообщения
+	// большего размера разбиваются на части, но не более чем на 128
+	ChunkSize int
+
+	// DialTimeout
+	// Время ожидания подключения, по умолчанию - DefaultDialTimeout
+	DialTimeout time.Duration
+
+	// WriteTimeout
+	// Время ожидания записи сообщения, по умолчанию - DefaultWriteTimeout. Нулевое значение отключает ограничение
+	WriteTimeout time.Duration
+}
+
+// GELFOption
+// Опция-функция для настройки GELF
+type GELFOption func(o GELFOptions) GELFOptions
+
+// WithGELFCompression
+// Определяет способ сжатия сообщений, передаваемых по UDP
+func WithGELFCompression(compression Compression) GELFOption {
+	return func(o GELFOptions) GELFOptions {
+		o.Compression = compression
+		return o
+	}
+}
+
+// WithGELFChunkSize
+// Определяет максимальный размер датаграммы UDP, размер должен превышать размер заголовка части (12 байт)
+func WithGELFChunkSize(size int) GELFOption {
+	return func(o GELFOptions) GELFOptions {
+		if size > _gelfChunkHeaderSize {
+			o.ChunkSize = size
+		}
+
+		return o
+	}
+}
+
+// WithGELFDialTimeout
+// Определяет время ожидания подключения
+func WithGELFDialTimeout(d time.Duration) GELFOption {
+	return func(o GELFOptions) GELFOptions {
+		o.DialTimeout = d
+		return o
+	}
+}
+
+// WithGELFWriteTimeout
+// Определяет время ожидания записи сообщения, по истечении которого соединение устанавливается заново
+func WithGELFWriteTimeout(d time.Duration) GELFOption {
+	return func(o GELFOptions) GELFOptions {
+		o.WriteTimeout = d
+		return o
+	}
+}
+
+// GELF
+// Поток вывода, отправляющий логи в формате log.FormatGELF на сервер Graylog по UDP или TCP. Каждый вызов Write
+// отправляет одно сообщение, завершающий перевод строки удаляется. По UDP сообщение сжимается и при превышении
+// размера датаграммы разбивается на части, по TCP сообщения разделяются нулевым байтом. При ошибке записи
+// соединение устанавливается заново и запись повторяется один раз. Безопасен для конкурентного использования
+type GELF struct {
+	network string
+	addr    string
+	stream  bool
+	opt     GELFOptions
+
+	mu     sync.Mutex
+	conn   net.Conn
+	buf    bytes.Buffer
+	zw     io.WriteCloser
+	chunk  []byte
+	closed bool
+}
+
+// NewGELF
+// Создает GELF и подключается к серверу. Поддерживаемые типы сети: udp, udp4, udp6, tcp, tcp4 и tcp6
+func NewGELF(network, addr string, options ...GELFOption) (*GELF, error) {
+	var stream bool
+
+	switch network {
+	case "tcp", "tcp4", "tcp6":
+		stream = true
+	case "udp", "udp4", "udp6":
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedNetwork, network)
+	}
+
+	opt := GELFOptions{
+		Compression:  CompressionNone,
+		ChunkSize:    DefaultGELFChunkSize,
+		DialTimeout:  DefaultDialTimeout,
+		WriteTimeout: DefaultWriteTimeout,
+	}
+
+	for _, option := range options {
+		opt = option(opt)
+	}
+
+	g := &GELF{
+		network: network,
+		addr:    addr,
+		stream:  stream,
+		opt:     opt,
+	}
+
+	if err := g.dial(); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+func (g *GELF) Write(p []byte) (int, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.closed {
+		return 0, ErrClosed
+	}
+
+	msg, err := g.encode(bytes.TrimSuffix(p, []byte{'\n'}))
+	if err != nil {
+		return 0, err
+	}
+
+	if !g.stream && g.chunks(msg) > _gelfMaxChunks {
+		return 0, fmt.Errorf("%w: %d bytes in %d chunks", ErrMessageTooLarge, len(msg), g.chunks(msg))
+	}
+
+	if g.conn != nil {
+		if err := g.send(msg); err == nil {
+			return len(p), nil
+		}
+
+		_ = g.conn.Close()
+		g.conn = nil
+	}
+
+	if err := g.dial(); err != nil {
+		return 0, err
+	}
+
+	if err := g.send(msg); err != nil {
+		_ = g.conn.Close()
+		g.conn = nil
+
+		return 0, fmt.Errorf("write %s %s: %w", g.network, g.addr, err)
+	}
+
+	return len(p), nil
+}
+
+// Close
+// Закрывает соединение, последующие вызовы Write возвращают ErrClosed
+func (g *GELF) Close() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.closed = true
+
+	if g.conn == nil {
+		return nil
+	}
+
+	err := g.conn.Close()
+	g.conn = nil
+
+	return err
+}
+
+// encode
+// Возвращает сообщение для отправки: по TCP - с завершающим нулевым байтом, по UDP - сжатое
+func (g *GELF) encode(msg []byte) ([]byte, error) {
+	g.buf.Reset()
+
+	if g.stream {
+		g.buf.Write(msg)
+		g.buf.WriteByte(0)
+
+		return g.buf.Bytes(), nil
+	}
+
+	if g.opt.Compression == CompressionNone {
+		return msg, nil
+	}
+
+	if err := g.compress(msg); err != nil {
+		return nil, fmt.Errorf("compress gelf: %w", err)
+	}
+
+	return g.buf.Bytes(), nil
+}
+
+// compress
+// Сжимает сообщение в buf
+func (g *GELF) compress(msg []byte) error {
+	switch {
+	case g.zw == nil && g.opt.Compression == CompressionZlib:
+		g.zw = zlib.NewWriter(&g.buf)
+	case g.zw == nil:
+		g.zw = gzip.NewWriter(&g.buf)
+	case g.opt.Compression == CompressionZlib:
+		g.zw.(*zlib.Writer).Reset(&g.buf) //nolint:forcetypeassert // writer matches compression
+	default:
+		g.zw.(*gzip.Writer).Reset(&g.buf) //nolint:forcetypeassert // writer matches compression
+	}
+
+	if _, err := g.zw.Write(msg); err != nil {
+		return err
+	}
+
+	return g.zw.Close()
+}
+
+// send
+// Отправляет сообщение, по UDP сообщение, превышающее размер датаграммы, разбивается на части
+func (g *GELF) send(msg []byte) error {
+	if g.stream || len(msg) <= g.opt.ChunkSize {
+		_, err := writeDeadline(g.conn, msg, g.opt.WriteTimeout)
+		return err
+	}
+
+	size := g.opt.ChunkSize - _gelfChunkHeaderSize
+	count := g.chunks(msg)
+	id := rand.Uint64() //nolint:gosec // message id must be unique, not secure
+
+	for i := range count {
+		g.chunk = append(g.chunk[:0], _gelfChunkMagic[:]...)
+		g.chunk = binary.BigEndian.AppendUint64(g.chunk, id)
+		g.chunk = append(g.chunk, byte(i), byte(count))
+		g.chunk = append(g.chunk, msg[i*size:min((i+1)*size, len(msg))]...)
+
+		if _, err := writeDeadline(g.conn, g.chunk, g.opt.WriteTimeout); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// chunks
+// Возвращает количество частей, на которые разбивается сообщение при передаче по UDP
+func (g *GELF) chunks(msg []byte) int {
+	if len(msg) <= g.opt.ChunkSize {
+		return 1
+	}
+
+	size := g.opt.ChunkSize - _gelfChunkHeaderSize
+
+	return (len(msg) + size - 1) / size
+}
+
+// dial
+// Устанавливает соединение с сервером
+func (g *GELF) dial() error {
+	conn, err := net.DialTimeout(g.network, g.addr, g.opt.DialTimeout)
+	if err != nil {
+		return fmt.Errorf("dial %s %s: %w", g.network, g.addr, err)
+	}
+
+	g.conn = conn
+	return nil
+}
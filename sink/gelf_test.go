@@ -0,0 +1,208 @@
+package sink
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anticrew/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readGELF
+// Читает сообщение GELF из UDP, собирая его из частей, и возвращает сообщение и количество частей
+func readGELF(t *testing.T, pc net.PacketConn) ([]byte, int) {
+	t.Helper()
+
+	require.NoError(t, pc.SetReadDeadline(time.Now().Add(time.Second)))
+
+	var (
+		id     []byte
+		chunks [][]byte
+		count  = 1
+	)
+
+	for received := 0; received < count; received++ {
+		buf := make([]byte, 65536)
+
+		n, _, err := pc.ReadFrom(buf)
+		require.NoError(t, err)
+
+		buf = buf[:n]
+		if !bytes.HasPrefix(buf, _gelfChunkMagic[:]) {
+			return buf, 1
+		}
+
+		if id == nil {
+			id = buf[2:10]
+			count = int(buf[11])
+			chunks = make([][]byte, count)
+		}
+
+		require.Equal(t, id, buf[2:10])
+		require.Equal(t, count, int(buf[11]))
+		chunks[buf[10]] = buf[12:]
+	}
+
+	return bytes.Join(chunks, nil), count
+}
+
+func Test_GELF_UDP(t *testing.T) {
+	t.Parallel()
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer pc.Close()
+
+	g, err := NewGELF("udp", pc.LocalAddr().String())
+	require.NoError(t, err)
+	defer g.Close()
+
+	l := log.NewLogger(log.WithWriter(g), log.WithFormat(log.FormatGELF))
+	l.Warn(log.NoContext, nil, "hello", log.String("key", "value"))
+
+	msg, count := readGELF(t, pc)
+	assert.Equal(t, 1, count)
+
+	var m map[string]any
+	require.NoError(t, json.Unmarshal(msg, &m), string(msg))
+	assert.Equal(t, "hello", m["short_message"])
+	assert.Equal(t, "value", m["_key"])
+	assert.InDelta(t, 4, m["level"], 0)
+}
+
+func Test_GELF_Chunking(t *testing.T) {
+	t.Parallel()
+
+	for compression, decompress := range map[Compression]func(r io.Reader) (io.Reader, error){
+		CompressionNone: func(r io.Reader) (io.Reader, error) { return r, nil },
+		CompressionGzip: func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) },
+		CompressionZlib: func(r io.Reader) (io.Reader, error) { return zlib.NewReader(r) },
+	} {
+		pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+		require.NoError(t, err)
+
+		g, err := NewGELF("udp", pc.LocalAddr().String(), WithGELFCompression(compression), WithGELFChunkSize(100))
+		require.NoError(t, err)
+
+		// несжимаемое содержимое, чтобы сообщение разбивалось на части при любом способе сжатия
+		value := make([]byte, 0, 2000)
+		for i := range cap(value) / 2 {
+			value = append(value, "0123456789abcdef"[i*7%16], "0123456789abcdef"[i*i%16])
+		}
+
+		l := log.NewLogger(log.WithWriter(g), log.WithFormat(log.FormatGELF))
+		l.Info(log.NoContext, "large", log.String("value", string(value)))
+
+		msg, count := readGELF(t, pc)
+		assert.Greater(t, count, 1, compression)
+
+		r, err := decompress(bytes.NewReader(msg))
+		require.NoError(t, err, compression)
+
+		var m map[string]any
+		require.NoError(t, json.NewDecoder(r).Decode(&m), compression)
+		assert.Equal(t, "large", m["short_message"], compression)
+		assert.Equal(t, string(value), m["_value"], compression)
+
+		require.NoError(t, g.Close())
+		require.NoError(t, pc.Close())
+	}
+}
+
+func Test_GELF_TooLarge(t *testing.T) {
+	t.Parallel()
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer pc.Close()
+
+	g, err := NewGELF("udp", pc.LocalAddr().String(), WithGELFChunkSize(13))
+	require.NoError(t, err)
+	defer g.Close()
+
+	_, err = g.Write([]byte(strings.Repeat("x", 129)))
+	require.ErrorIs(t, err, ErrMessageTooLarge)
+
+	_, err = g.Write([]byte(strings.Repeat("x", 128)))
+	require.NoError(t, err)
+}
+
+func Test_GELF_TCP(t *testing.T) {
+	t.Parallel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	g, err := NewGELF("tcp", ln.Addr().String(), WithGELFCompression(CompressionGzip))
+	require.NoError(t, err)
+	defer g.Close()
+
+	conn, err := ln.Accept()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	l := log.NewLogger(log.WithWriter(g), log.WithFormat(log.FormatGELF))
+	l.Info(log.NoContext, "first")
+	l.Info(log.NoContext, "second")
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	r := bufio.NewReader(conn)
+
+	for _, want := range []string{"first", "second"} {
+		msg, err := r.ReadBytes(0)
+		require.NoError(t, err)
+
+		var m map[string]any
+		require.NoError(t, json.Unmarshal(bytes.TrimSuffix(msg, []byte{0}), &m), string(msg))
+		assert.Equal(t, want, m["short_message"])
+	}
+}
+
+func Test_GELF_WriteTimeout(t *testing.T) {
+	t.Parallel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	g, err := NewGELF("tcp", ln.Addr().String(), WithGELFWriteTimeout(50*time.Millisecond))
+	require.NoError(t, err)
+	defer g.Close()
+
+	start := time.Now()
+	require.ErrorIs(t, writeStalled(t, g), os.ErrDeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func Test_NewGELF_UnsupportedNetwork(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewGELF("unix", "/tmp/gelf.sock")
+	require.ErrorIs(t, err, ErrUnsupportedNetwork)
+}
+
+func Test_GELF_Closed(t *testing.T) {
+	t.Parallel()
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer pc.Close()
+
+	g, err := NewGELF("udp", pc.LocalAddr().String())
+	require.NoError(t, err)
+	require.NoError(t, g.Close())
+
+	_, err = g.Write([]byte("{}"))
+	require.ErrorIs(t, err, ErrClosed)
+}
@@ -0,0 +1,517 @@
+package sink
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultHTTPTimeout
+	// Время ожидания ответа сервера по умолчанию
+	DefaultHTTPTimeout = 10 * time.Second
+
+	// DefaultCloseTimeout
+	// Время ожидания отправки накопленных логов при закрытии по умолчанию
+	DefaultCloseTimeout = 5 * time.Second
+)
+
+// _maxResponseSize
+// Максимальный размер тела ответа сервера, передаваемого в ResponseChecker
+const _maxResponseSize = 16 << 20
+
+// compressBody
+// Сжимает тело запроса и возвращает его вместе со значением заголовка Content-Encoding
+func compressBody(compression Compression, body []byte) ([]byte, string, error) {
+	var (
+		buf      bytes.Buffer
+		w        io.WriteCloser
+		encoding string
+	)
+
+	switch compression { //nolint:exhaustive // other values mean no compression
+	case CompressionGzip:
+		w, encoding = gzip.NewWriter(&buf), "gzip"
+	case CompressionZlib:
+		w, encoding = zlib.NewWriter(&buf), "deflate"
+	default:
+		return body, "", nil
+	}
+
+	if _, err := w.Write(body); err != nil {
+		return nil, "", err
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, "", err
+	}
+
+	return buf.Bytes(), encoding, nil
+}
+
+// post
+// Отправляет запрос POST и возвращает тело успешного ответа. Сетевые ошибки и статусы 429, 502, 503 и 504
+// возвращаются как retryableError с задержкой из заголовка Retry-After
+func post(ctx context.Context, client *http.Client, url string, header http.Header, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("post %s: %w", url, err)
+	}
+
+	req.Header = header.Clone()
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, &retryableError{err: fmt.Errorf("post %s: %w", url, err)}
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		data, err := io.ReadAll(io.LimitReader(resp.Body, _maxResponseSize))
+		if err != nil {
+			return nil, &retryableError{err: fmt.Errorf("post %s: read response: %w", url, err)}
+		}
+
+		return data, nil
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	err = fmt.Errorf("post %s: %w: %s", url, ErrUnexpectedStatus, resp.Status)
+
+	if retryableStatus(resp.StatusCode) {
+		return nil, &retryableError{err: err, after: retryAfter(resp)}
+	}
+
+	return nil, err
+}
+
+// retryableStatus
+// Сообщает, допустима ли повторная отправка после ответа с указанным статусом: 429, 502, 503 или 504
+func retryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryAfter
+// Возвращает задержку из заголовка Retry-After в секундах, 0 - если заголовок отсутствует или указан датой
+func retryAfter(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+
+	return time.Duration(seconds) * time.Second
+}
+
+const (
+	// DefaultHTTPBatchSize
+	// Максимальное кол-во логов в одном запросе по умолчанию
+	DefaultHTTPBatchSize = 512
+
+	// DefaultHTTPFlushInterval
+	// Интервал отправки накопленных логов по умолчанию
+	DefaultHTTPFlushInterval = time.Second
+
+	// DefaultHTTPQueueSize
+	// Максимальное кол-во логов, ожидающих отправки, по умолчанию
+	DefaultHTTPQueueSize = 2048
+
+	// DefaultHTTPBufferSize
+	// Максимальный размер буфера на диске по умолчанию
+	DefaultHTTPBufferSize = 100 << 20
+)
+
+// HTTPOptions
+// Настройки HTTP
+type HTTPOptions struct {
+	// Client
+	// HTTP-клиент, по умолчанию - http.Client с таймаутом DefaultHTTPTimeout
+	Client *http.Client
+
+	// Headers
+	// Дополнительные заголовки запроса, например, для авторизации
+	Headers map[string]string
+
+	// Compression
+	// Способ сжатия тела запроса, по умолчанию - CompressionNone. CompressionZlib передается как deflate
+	Compression Compression
+
+	// BatchSize
+	// Максимальное кол-во логов в одном запросе, по умолчанию - DefaultHTTPBatchSize
+	BatchSize int
+
+	// FlushInterval
+	// Интервал отправки накопленных логов, по умолчанию - DefaultHTTPFlushInterval
+	FlushInterval time.Duration
+
+	// QueueSize
+	// Максимальное кол-во логов, ожидающих отправки, по умолчанию - DefaultHTTPQueueSize. При заполнении очереди Write
+	// возвращает ErrQueueFull
+	QueueSize int
+
+	// Backoff
+	// Политика повторных попыток отправки, по умолчанию - DefaultBackoff
+	Backoff Backoff
+
+	// BufferDir
+	// Каталог буфера на диске, по умолчанию - "", буфер не используется. Пакеты, которые не удалось отправить из-за
+	// недоступности сервера, сохраняются в буфер и отправляются после восстановления связи, в том числе после
+	// перезапуска процесса
+	BufferDir string
+
+	// BufferSize
+	// Максимальный размер буфера на диске в байтах, по умолчанию - DefaultHTTPBufferSize. При превышении удаляются
+	// самые старые пакеты
+	BufferSize int64
+
+	// CloseTimeout
+	// Время ожидания отправки накопленных логов при Close, по умолчанию - DefaultCloseTimeout. Нулевое значение
+	// отключает ограничение
+	CloseTimeout time.Duration
+
+	// ErrorHandler
+	// Функция, вызываемая при ошибке отправки после исчерпания попыток, по умолчанию - nil
+	ErrorHandler func(err error)
+}
+
+// HTTPOption
+// Опция-функция для настройки HTTP
+type HTTPOption func(o HTTPOptions) HTTPOptions
+
+// WithHTTPClient
+// Определяет HTTP-клиент
+func WithHTTPClient(client *http.Client) HTTPOption {
+	return func(o HTTPOptions) HTTPOptions {
+		if client != nil {
+			o.Client = client
+		}
+
+		return o
+	}
+}
+
+// WithHTTPHeaders
+// Добавляет заголовки запроса
+func WithHTTPHeaders(headers map[string]string) HTTPOption {
+	headers = maps.Clone(headers)
+
+	return func(o HTTPOptions) HTTPOptions {
+		o.Headers = maps.Clone(o.Headers)
+		if o.Headers == nil {
+			o.Headers = make(map[string]string, len(headers))
+		}
+
+		maps.Copy(o.Headers, headers)
+		return o
+	}
+}
+
+// WithHTTPCompression
+// Определяет способ сжатия тела запроса
+func WithHTTPCompression(compression Compression) HTTPOption {
+	return func(o HTTPOptions) HTTPOptions {
+		o.Compression = compression
+		return o
+	}
+}
+
+// WithHTTPBatch
+// Определяет максимальное кол-во логов в одном запросе и интервал отправки накопленных логов
+func WithHTTPBatch(size int, interval time.Duration) HTTPOption {
+	return func(o HTTPOptions) HTTPOptions {
+		if size > 0 {
+			o.BatchSize = size
+		}
+
+		if interval > 0 {
+			o.FlushInterval = interval
+		}
+
+		return o
+	}
+}
+
+// WithHTTPQueueSize
+// Определяет максимальное кол-во логов, ожидающих отправки
+func WithHTTPQueueSize(size int) HTTPOption {
+	return func(o HTTPOptions) HTTPOptions {
+		if size > 0 {
+			o.QueueSize = size
+		}
+
+		return o
+	}
+}
+
+// WithHTTPBackoff
+// Определяет политику повторных попыток отправки
+func WithHTTPBackoff(backoff Backoff) HTTPOption {
+	return func(o HTTPOptions) HTTPOptions {
+		o.Backoff = backoff
+		return o
+	}
+}
+
+// WithHTTPBuffer
+// Включает буфер на диске в каталоге dir размером не более size байт
+func WithHTTPBuffer(dir string, size int64) HTTPOption {
+	return func(o HTTPOptions) HTTPOptions {
+		o.BufferDir = dir
+		if size > 0 {
+			o.BufferSize = size
+		}
+
+		return o
+	}
+}
+
+// WithHTTPCloseTimeout
+// Определяет время ожидания отправки накопленных логов при Close
+func WithHTTPCloseTimeout(d time.Duration) HTTPOption {
+	return func(o HTTPOptions) HTTPOptions {
+		o.CloseTimeout = d
+		return o
+	}
+}
+
+// WithHTTPErrorHandler
+// Определяет функцию, вызываемую при ошибке отправки после исчерпания попыток
+func WithHTTPErrorHandler(handler func(err error)) HTTPOption {
+	return func(o HTTPOptions) HTTPOptions {
+		o.ErrorHandler = handler
+		return o
+	}
+}
+
+// HTTP
+// Поток вывода, отправляющий логи пакетами по HTTP. Тело запроса формирует Encoder, например, LokiEncoder или
+// ElasticsearchEncoder. Пакет отправляется при накоплении BatchSize логов или по истечении FlushInterval в отдельной
+// горутине с повторными попытками. Если Encoder реализует ResponseChecker, повторно отправляются только логи,
+// отклоненные сервером с возможностью повтора. Если сервер недоступен, пакет сохраняется в буфер на диске и
+// отправляется перед следующим пакетом; пока буфер не пуст, выполняется одна попытка отправки. Close отправляет
+// накопленные логи. Безопасен для конкурентного использования
+type HTTP struct {
+	url     string
+	encoder Encoder
+	opt     HTTPOptions
+	header  http.Header
+	spool   *spool
+
+	batch *batcher[Record]
+}
+
+// NewHTTP
+// Создает HTTP, отправляющий логи на url в формате encoder. Возвращает ошибку, если не удалось открыть буфер на диске
+func NewHTTP(url string, encoder Encoder, options ...HTTPOption) (*HTTP, error) {
+	opt := HTTPOptions{
+		Client:        &http.Client{Timeout: DefaultHTTPTimeout},
+		Compression:   CompressionNone,
+		BatchSize:     DefaultHTTPBatchSize,
+		FlushInterval: DefaultHTTPFlushInterval,
+		QueueSize:     DefaultHTTPQueueSize,
+		Backoff:       DefaultBackoff,
+		BufferSize:    DefaultHTTPBufferSize,
+		CloseTimeout:  DefaultCloseTimeout,
+	}
+
+	for _, option := range options {
+		opt = option(opt)
+	}
+
+	header := make(http.Header, len(opt.Headers)+2)
+	for k, v := range opt.Headers {
+		header.Set(k, v)
+	}
+
+	header.Set("Content-Type", encoder.ContentType())
+
+	h := &HTTP{
+		url:     url,
+		encoder: encoder,
+		opt:     opt,
+		header:  header,
+	}
+
+	if len(opt.BufferDir) > 0 {
+		s, err := newSpool(opt.BufferDir, opt.BufferSize)
+		if err != nil {
+			return nil, err
+		}
+
+		h.spool = s
+	}
+
+	h.batch = newBatcher(opt.BatchSize, opt.QueueSize, opt.FlushInterval, h.export, h.idle)
+
+	return h, nil
+}
+
+func (h *HTTP) Write(p []byte) (int, error) {
+	r := Record{
+		Time: time.Now(),
+		Data: bytes.Clone(bytes.TrimSuffix(p, []byte{'\n'})),
+	}
+
+	if err := h.batch.add(r); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Close
+// Отправляет накопленные логи и пакеты из буфера на диске и останавливает отправку, последующие вызовы Write
+// возвращают ErrClosed. Пакеты, которые не удалось отправить, остаются в буфере на диске. Если отправка не
+// завершилась за CloseTimeout, она прерывается: оставшиеся пакеты сохраняются в буфер на диске, а без него
+// отбрасываются с передачей ошибки в ErrorHandler, Close возвращает ErrCloseTimeout
+func (h *HTTP) Close() error {
+	return h.batch.close(h.opt.CloseTimeout)
+}
+
+// export
+// Отправляет пакет логов, предварительно отправив пакеты из буфера на диске
+func (h *HTTP) export(ctx context.Context, records []Record) {
+	body, err := h.encoder.Encode(nil, records)
+	if err != nil {
+		h.report(fmt.Errorf("encode: %w", err))
+		return
+	}
+
+	if err := h.replay(ctx); err != nil {
+		h.save(body, err)
+		return
+	}
+
+	rest, retryable, err := h.send(ctx, h.opt.Backoff, body)
+	if err == nil {
+		return
+	}
+
+	if retryable && h.spool != nil {
+		h.save(rest, err)
+		return
+	}
+
+	h.report(err)
+}
+
+// idle
+// Отправляет пакеты из буфера на диске, когда новых логов нет, чтобы буфер освобождался и без новых записей. Ошибка
+// отправки не передается в ErrorHandler: она уже была передана при сохранении пакетов, которые остаются в буфере
+func (h *HTTP) idle(ctx context.Context) {
+	_ = h.replay(ctx)
+}
+
+// replay
+// Отправляет пакеты из буфера на диске одной попыткой каждый. Пакет, отклоненный сервером, удаляется из буфера, чтобы
+// не блокировать отправку остальных
+func (h *HTTP) replay(ctx context.Context) error {
+	if h.spool == nil || h.spool.pending == 0 {
+		return nil
+	}
+
+	return h.spool.replay(func(body []byte) ([]byte, error) {
+		rest, retryable, err := h.send(ctx, Backoff{Attempts: 1}, body)
+		if err != nil && !retryable {
+			h.report(err)
+			return nil, nil
+		}
+
+		return rest, err
+	})
+}
+
+// send
+// Отправляет тело запроса с повторными попытками. Возвращает тело запроса с логами, которые не удалось отправить, и
+// сообщает, допустима ли их повторная отправка. Логи, отклоненные сервером без возможности повтора, передаются в
+// ErrorHandler
+func (h *HTTP) send(ctx context.Context, backoff Backoff, body []byte) ([]byte, bool, error) {
+	payload, header, err := h.request(body)
+	if err != nil {
+		return body, false, err
+	}
+
+	checker, _ := h.encoder.(ResponseChecker)
+
+	var retryable bool
+
+	err = backoff.do(ctx, func() error {
+		resp, err := post(ctx, h.opt.Client, h.url, header, payload)
+		if err == nil && checker != nil {
+			if retry := h.check(checker, body, resp); len(retry) > 0 {
+				body = retry
+
+				if payload, header, err = h.request(body); err == nil {
+					err = &retryableError{err: fmt.Errorf("post %s: %w", h.url, ErrPartialFailure)}
+				}
+			}
+		}
+
+		var re *retryableError
+		retryable = errors.As(err, &re)
+
+		return err
+	})
+
+	return body, retryable, err
+}
+
+// check
+// Проверяет ответ сервера с помощью ResponseChecker, передает ошибку для отклоненных логов в ErrorHandler и
+// возвращает тело запроса с логами, которые необходимо отправить повторно
+func (h *HTTP) check(checker ResponseChecker, body, resp []byte) []byte {
+	retry, err := checker.CheckResponse(body, resp)
+	if err != nil {
+		h.report(fmt.Errorf("post %s: %w", h.url, err))
+	}
+
+	return retry
+}
+
+// request
+// Возвращает сжатое тело запроса и заголовки запроса
+func (h *HTTP) request(body []byte) ([]byte, http.Header, error) {
+	payload, encoding, err := compressBody(h.opt.Compression, body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	header := h.header
+	if len(encoding) > 0 {
+		header = header.Clone()
+		header.Set("Content-Encoding", encoding)
+	}
+
+	return payload, header, nil
+}
+
+// save
+// Сохраняет пакет в буфер на диске после ошибки отправки err
+func (h *HTTP) save(body []byte, err error) {
+	if serr := h.spool.save(body); serr != nil {
+		h.report(errors.Join(err, serr))
+		return
+	}
+
+	h.report(fmt.Errorf("%w (buffered)", err))
+}
+
+func (h *HTTP) report(err error) {
+	if h.opt.ErrorHandler != nil {
+		h.opt.ErrorHandler(fmt.Errorf("export http: %w", err))
+	}
+}
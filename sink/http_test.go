@@ -0,0 +1,490 @@
+package sink
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/anticrew/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_HTTP_Loki(t *testing.T) {
+	t.Parallel()
+
+	c := &httpReceiver{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/loki/api/v1/push", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "tenant", r.Header.Get("X-Scope-OrgID"))
+		c.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	h, err := NewHTTP(server.URL+"/loki/api/v1/push",
+		NewLokiEncoder(map[string]string{"service.name": "app"}, log.LevelKey, "user"),
+		WithHTTPHeaders(map[string]string{"X-Scope-OrgID": "tenant"}),
+		WithHTTPBatch(10, time.Hour),
+	)
+	require.NoError(t, err)
+
+	l := log.NewLogger(log.WithWriter(h), log.WithFormat(log.FormatJSON))
+
+	l.Info(log.NoContext, "first", log.Int("user", 1))
+	l.Warn(log.NoContext, nil, "second")
+	l.Info(log.NoContext, "third", log.Int("user", 1))
+
+	require.NoError(t, l.Close())
+
+	bodies := c.get()
+	require.Len(t, bodies, 1)
+
+	var req struct {
+		Streams []struct {
+			Stream map[string]string
+			Values [][2]string
+		}
+	}
+
+	require.NoError(t, json.Unmarshal(bodies[0], &req))
+	require.Len(t, req.Streams, 2)
+
+	assert.Equal(t, map[string]string{"service_name": "app", "level": "INFO", "user": "1"}, req.Streams[0].Stream)
+	assert.Equal(t, map[string]string{"service_name": "app", "level": "WARN"}, req.Streams[1].Stream)
+
+	require.Len(t, req.Streams[0].Values, 2)
+	require.Len(t, req.Streams[1].Values, 1)
+
+	for i, msg := range []string{"first", "third"} {
+		value := req.Streams[0].Values[i]
+		assert.NotEmpty(t, value[0])
+
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal([]byte(value[1]), &entry))
+		assert.Equal(t, msg, entry[log.MessageKey])
+	}
+}
+
+func Test_HTTP_Elasticsearch(t *testing.T) {
+	t.Parallel()
+
+	c := &httpReceiver{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/x-ndjson", r.Header.Get("Content-Type"))
+		assert.Equal(t, "gzip", r.Header.Get("Content-Encoding"))
+		c.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	h, err := NewHTTP(server.URL+"/_bulk", NewElasticsearchEncoder("logs-app"),
+		WithHTTPCompression(CompressionGzip),
+		WithHTTPBatch(2, time.Hour),
+	)
+	require.NoError(t, err)
+
+	l := log.NewLogger(log.WithWriter(h), log.WithFormat(log.FormatJSON))
+
+	for _, msg := range []string{"a", "b", "c"} {
+		l.Info(log.NoContext, msg)
+	}
+
+	require.NoError(t, l.Close())
+
+	bodies := c.get()
+	require.Len(t, bodies, 2)
+
+	var lines [][]byte
+	for _, body := range bodies {
+		assert.Equal(t, byte('\n'), body[len(body)-1])
+
+		scanner := bufio.NewScanner(bytes.NewReader(body))
+		for scanner.Scan() {
+			lines = append(lines, bytes.Clone(scanner.Bytes()))
+		}
+	}
+
+	require.Len(t, lines, 6)
+
+	for i, msg := range []string{"a", "b", "c"} {
+		assert.JSONEq(t, `{"create":{"_index":"logs-app"}}`, string(lines[i*2]))
+
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(lines[i*2+1], &entry))
+		assert.Equal(t, msg, entry[log.MessageKey])
+	}
+}
+
+func Test_HTTP_Elasticsearch_PartialFailure(t *testing.T) {
+	t.Parallel()
+
+	var (
+		c    = &httpReceiver{}
+		errs []error
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.ServeHTTP(w, r)
+
+		if len(c.get()) == 1 {
+			_, _ = w.Write([]byte(`{"errors":true,"items":[` +
+				`{"create":{"status":201}},` +
+				`{"create":{"status":429,"error":{"type":"es_rejected_execution_exception","reason":"queue full"}}},` +
+				`{"create":{"status":400,"error":{"type":"mapper_parsing_exception","reason":"bad field"}}}]}`))
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"errors":false,"items":[{"create":{"status":201}}]}`))
+	}))
+	defer server.Close()
+
+	h, err := NewHTTP(server.URL, NewElasticsearchEncoder("logs"),
+		WithHTTPBatch(3, time.Hour),
+		WithHTTPBackoff(Backoff{Attempts: 2, Initial: time.Millisecond, Max: time.Millisecond}),
+		WithHTTPErrorHandler(func(err error) {
+			errs = append(errs, err)
+		}),
+	)
+	require.NoError(t, err)
+
+	for _, msg := range []string{"created", "retried", "rejected"} {
+		_, err = h.Write([]byte(`{"msg":"` + msg + `"}`))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, h.Close())
+
+	bodies := c.get()
+	require.Len(t, bodies, 2)
+	assert.Equal(t, `{"create":{"_index":"logs"}}`+"\n"+`{"msg":"retried"}`+"\n", string(bodies[1]))
+
+	require.Len(t, errs, 1)
+	require.ErrorIs(t, errs[0], ErrPartialFailure)
+	assert.Contains(t, errs[0].Error(), "item 2: status 400: mapper_parsing_exception: bad field")
+}
+
+func Test_HTTP_CloseTimeout(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+
+	var handled atomic.Int32
+
+	h, err := NewHTTP(server.URL, NewElasticsearchEncoder("logs"),
+		WithHTTPBatch(1, time.Hour),
+		WithHTTPBackoff(Backoff{Attempts: 10, Initial: time.Hour, Max: time.Hour}),
+		WithHTTPBuffer(dir, 0),
+		WithHTTPCloseTimeout(50*time.Millisecond),
+		WithHTTPErrorHandler(func(error) {
+			handled.Add(1)
+		}),
+	)
+	require.NoError(t, err)
+
+	for _, msg := range []string{"a", "b"} {
+		_, err = h.Write([]byte(`{"msg":"` + msg + `"}`))
+		require.NoError(t, err)
+	}
+
+	start := time.Now()
+	require.ErrorIs(t, h.Close(), ErrCloseTimeout)
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	// Пакеты, которые не удалось отправить до истечения CloseTimeout, сохраняются в буфер на диске
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 2)
+	assert.Equal(t, int32(2), handled.Load())
+}
+
+func Test_HTTP_Retry(t *testing.T) {
+	t.Parallel()
+
+	var attempts atomic.Int32
+
+	c := &httpReceiver{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		c.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	h, err := NewHTTP(server.URL, NewElasticsearchEncoder("logs"),
+		WithHTTPBackoff(Backoff{Attempts: 3, Initial: time.Millisecond, Max: 10 * time.Millisecond, Jitter: 0.5}),
+	)
+	require.NoError(t, err)
+
+	_, err = h.Write([]byte(`{"msg":"retry"}` + "\n"))
+	require.NoError(t, err)
+	require.NoError(t, h.Close())
+
+	assert.Equal(t, int32(3), attempts.Load())
+	assert.Equal(t, [][]byte{[]byte(`{"create":{"_index":"logs"}}` + "\n" + `{"msg":"retry"}` + "\n")}, c.get())
+}
+
+func Test_HTTP_Buffer(t *testing.T) {
+	t.Parallel()
+
+	var (
+		down   atomic.Bool
+		errs   atomic.Int32
+		dir    = filepath.Join(t.TempDir(), "buffer")
+		encode = NewElasticsearchEncoder("logs")
+	)
+
+	down.Store(true)
+
+	c := &httpReceiver{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		c.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	options := []HTTPOption{
+		WithHTTPBatch(1, time.Hour),
+		WithHTTPBackoff(Backoff{Attempts: 2, Initial: time.Millisecond, Max: time.Millisecond}),
+		WithHTTPBuffer(dir, 0),
+		WithHTTPCompression(CompressionGzip),
+		WithHTTPErrorHandler(func(err error) {
+			assert.ErrorIs(t, err, ErrUnexpectedStatus)
+			errs.Add(1)
+		}),
+	}
+
+	h, err := NewHTTP(server.URL, encode, options...)
+	require.NoError(t, err)
+
+	for _, msg := range []string{"a", "b"} {
+		_, err = h.Write([]byte(`{"msg":"` + msg + `"}`))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, h.Close())
+
+	assert.Equal(t, int32(2), errs.Load())
+	assert.Empty(t, c.get())
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	// Пакеты из буфера отправляются новым экземпляром перед собственными
+	down.Store(false)
+
+	h, err = NewHTTP(server.URL, encode, options...)
+	require.NoError(t, err)
+
+	_, err = h.Write([]byte(`{"msg":"c"}`))
+	require.NoError(t, err)
+	require.NoError(t, h.Close())
+
+	assert.Equal(t, int32(2), errs.Load())
+
+	var msgs []string
+	for _, body := range c.get() {
+		lines := bytes.Split(bytes.TrimSpace(body), []byte{'\n'})
+		require.Len(t, lines, 2)
+
+		var entry struct{ Msg string }
+		require.NoError(t, json.Unmarshal(lines[1], &entry))
+		msgs = append(msgs, entry.Msg)
+	}
+
+	assert.Equal(t, []string{"a", "b", "c"}, msgs)
+
+	files, err = os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, files)
+}
+
+func Test_HTTP_Buffer_Idle(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	s, err := newSpool(dir, 0)
+	require.NoError(t, err)
+	require.NoError(t, s.save([]byte("idle")))
+
+	c := &httpReceiver{}
+	server := httptest.NewServer(c)
+	defer server.Close()
+
+	h, err := NewHTTP(server.URL, NewLokiEncoder(nil), WithHTTPBatch(10, 10*time.Millisecond), WithHTTPBuffer(dir, 0))
+	require.NoError(t, err)
+	defer h.Close()
+
+	// Пакет из буфера отправляется по истечении FlushInterval без новых записей
+	require.Eventually(t, func() bool {
+		return len(c.get()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, "idle", string(c.get()[0]))
+}
+
+func Test_HTTP_Buffer_Close(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	s, err := newSpool(dir, 0)
+	require.NoError(t, err)
+	require.NoError(t, s.save([]byte("close")))
+
+	c := &httpReceiver{}
+	server := httptest.NewServer(c)
+	defer server.Close()
+
+	h, err := NewHTTP(server.URL, NewLokiEncoder(nil), WithHTTPBatch(10, time.Hour), WithHTTPBuffer(dir, 0))
+	require.NoError(t, err)
+	require.NoError(t, h.Close())
+
+	require.Len(t, c.get(), 1)
+	assert.Equal(t, "close", string(c.get()[0]))
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, files)
+}
+
+func Test_HTTP_Rejected(t *testing.T) {
+	t.Parallel()
+
+	var attempts atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+
+	var handled error
+
+	h, err := NewHTTP(server.URL, NewLokiEncoder(nil),
+		WithHTTPBuffer(dir, 0),
+		WithHTTPErrorHandler(func(err error) {
+			handled = err
+		}),
+	)
+	require.NoError(t, err)
+
+	_, err = h.Write([]byte(`{"msg":"bad"}`))
+	require.NoError(t, err)
+	require.NoError(t, h.Close())
+
+	assert.Equal(t, int32(1), attempts.Load())
+	assert.ErrorIs(t, handled, ErrUnexpectedStatus)
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, files)
+}
+
+func Test_HTTP_Closed(t *testing.T) {
+	t.Parallel()
+
+	h, err := NewHTTP("http://127.0.0.1:0", NewLokiEncoder(nil))
+	require.NoError(t, err)
+	require.NoError(t, h.Close())
+
+	_, err = h.Write([]byte("{}"))
+	assert.ErrorIs(t, err, ErrClosed)
+}
+
+func Test_spool_trim(t *testing.T) {
+	t.Parallel()
+
+	s, err := newSpool(t.TempDir(), 10)
+	require.NoError(t, err)
+
+	for _, body := range []string{"aaaa", "bbbb", "cccc"} {
+		require.NoError(t, s.save([]byte(body)))
+	}
+
+	assert.Equal(t, 2, s.pending)
+
+	var bodies []string
+	require.NoError(t, s.replay(func(body []byte) ([]byte, error) {
+		bodies = append(bodies, string(body))
+		return nil, nil
+	}))
+
+	assert.Equal(t, []string{"bbbb", "cccc"}, bodies)
+	assert.Equal(t, 0, s.pending)
+}
+
+func Test_spool_replay_Rest(t *testing.T) {
+	t.Parallel()
+
+	s, err := newSpool(t.TempDir(), 0)
+	require.NoError(t, err)
+	require.NoError(t, s.save([]byte("accepted,retry")))
+
+	err = s.replay(func([]byte) ([]byte, error) {
+		return []byte("retry"), assert.AnError
+	})
+	require.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, 1, s.pending)
+
+	var bodies []string
+	require.NoError(t, s.replay(func(body []byte) ([]byte, error) {
+		bodies = append(bodies, string(body))
+		return nil, nil
+	}))
+
+	assert.Equal(t, []string{"retry"}, bodies)
+}
+
+func Test_spool_clean(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "1"+_spoolTmpExt), []byte("partial"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "2"+_spoolExt), []byte("saved"), 0o600))
+
+	s, err := newSpool(dir, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, s.pending)
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "2"+_spoolExt, files[0].Name())
+}
+
+func Test_LokiEncoder_labelName(t *testing.T) {
+	t.Parallel()
+
+	for key, want := range map[string]string{
+		"level":        "level",
+		"log.level":    "log_level",
+		"1st":          "_st",
+		"http-status2": "http_status2",
+		"":             "_",
+	} {
+		assert.Equal(t, want, lokiLabelName(key), key)
+	}
+}
@@ -0,0 +1,26 @@
+package sink
+
+// JournalSocket
+// Сокет нативного протокола journald
+const JournalSocket = "/run/systemd/journal/socket"
+
+// JournalOptions
+// Настройки Journal
+type JournalOptions struct {
+	// Socket
+	// Путь к сокету journald, по умолчанию - JournalSocket
+	Socket string
+}
+
+// JournalOption
+// Опция-функция для настройки Journal
+type JournalOption func(o JournalOptions) JournalOptions
+
+// WithJournalSocket
+// Определяет путь к сокету journald
+func WithJournalSocket(path string) JournalOption {
+	return func(o JournalOptions) JournalOptions {
+		o.Socket = path
+		return o
+	}
+}
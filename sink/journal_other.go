@@ -0,0 +1,27 @@
+//go:build !unix
+
+package sink
+
+import (
+	"errors"
+)
+
+// Journal
+// journald поддерживается только в unix-системах
+type Journal struct{}
+
+// NewJournal
+// journald поддерживается только в unix-системах
+func NewJournal(...JournalOption) (*Journal, error) {
+	return nil, errors.ErrUnsupported
+}
+
+func (*Journal) Write([]byte) (int, error) {
+	return 0, errors.ErrUnsupported
+}
+
+// Close
+// Ничего не делает
+func (*Journal) Close() error {
+	return nil
+}
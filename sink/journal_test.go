@@ -0,0 +1,135 @@
+//go:build linux
+
+package sink
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/anticrew/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parseJournal
+// Разбирает запись в нативном протоколе journald
+func parseJournal(t *testing.T, p []byte) map[string]string {
+	t.Helper()
+
+	fields := make(map[string]string)
+
+	for len(p) > 0 {
+		i := strings.IndexAny(string(p), "=\n")
+		require.GreaterOrEqual(t, i, 0)
+
+		name := string(p[:i])
+
+		if p[i] == '=' {
+			end := strings.IndexByte(string(p[i:]), '\n')
+			require.GreaterOrEqual(t, end, 0)
+
+			fields[name] = string(p[i+1 : i+end])
+			p = p[i+end+1:]
+
+			continue
+		}
+
+		size := int(binary.LittleEndian.Uint64(p[i+1 : i+9]))
+		fields[name] = string(p[i+9 : i+9+size])
+		p = p[i+9+size+1:]
+	}
+
+	return fields
+}
+
+func listenJournal(t *testing.T) (*net.UnixConn, string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "journal.sock")
+
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	return conn, path
+}
+
+func Test_Journal(t *testing.T) {
+	t.Parallel()
+
+	server, path := listenJournal(t)
+
+	j, err := NewJournal(WithJournalSocket(path))
+	require.NoError(t, err)
+	defer j.Close()
+
+	l := log.NewLogger(log.WithWriter(j), log.WithFormat(log.FormatJournal), log.WithSource(""),
+		log.WithSyslog(log.FacilityDaemon, "app"))
+	l.Named("svc").Warn(log.NoContext, nil, "multi\nline", log.String("user.id", "42"))
+
+	buf := make([]byte, 4096)
+	n, err := server.Read(buf)
+	require.NoError(t, err)
+
+	fields := parseJournal(t, buf[:n])
+	assert.Equal(t, "multi\nline", fields["MESSAGE"])
+	assert.Equal(t, "4", fields["PRIORITY"])
+	assert.Equal(t, "3", fields["SYSLOG_FACILITY"])
+	assert.Equal(t, "app", fields["SYSLOG_IDENTIFIER"])
+	assert.Equal(t, "42", fields["USER_ID"])
+	assert.Equal(t, "svc", fields["LOGGER"])
+	assert.True(t, strings.HasSuffix(fields["CODE_FILE"], "sink/journal_test.go"), fields["CODE_FILE"])
+	assert.NotEmpty(t, fields["CODE_LINE"])
+	assert.Equal(t, "github.com/anticrew/log/sink.Test_Journal", fields["CODE_FUNC"])
+
+	require.NoError(t, j.Close())
+	_, err = j.Write([]byte("MESSAGE=closed\n"))
+	require.ErrorIs(t, err, ErrClosed)
+}
+
+func Test_Journal_Large(t *testing.T) {
+	t.Parallel()
+
+	server, path := listenJournal(t)
+
+	j, err := NewJournal(WithJournalSocket(path))
+	require.NoError(t, err)
+	defer j.Close()
+
+	entry := "MESSAGE=" + strings.Repeat("x", 1<<20) + "\n"
+
+	n, err := j.Write([]byte(entry))
+	require.NoError(t, err)
+	assert.Equal(t, len(entry), n)
+
+	oob := make([]byte, syscall.CmsgSpace(4))
+	_, oobn, _, _, err := server.ReadMsgUnix(nil, oob)
+	require.NoError(t, err)
+
+	msgs, err := syscall.ParseSocketControlMessage(oob[:oobn])
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+
+	fds, err := syscall.ParseUnixRights(&msgs[0])
+	require.NoError(t, err)
+	require.Len(t, fds, 1)
+
+	f := os.NewFile(uintptr(fds[0]), "journal")
+	defer f.Close()
+
+	_, err = f.Seek(0, io.SeekStart)
+	require.NoError(t, err)
+
+	data, err := io.ReadAll(f)
+	require.NoError(t, err)
+	assert.Equal(t, entry, string(data))
+}
@@ -0,0 +1,99 @@
+//go:build unix
+
+package sink
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"syscall"
+)
+
+// Journal
+// Поток вывода, отправляющий логи в формате log.FormatJournal в journald по нативному протоколу. Каждый вызов Write
+// отправляет одну запись отдельной датаграммой, записи, превышающие размер датаграммы, передаются через memfd.
+// Безопасен для конкурентного использования
+type Journal struct {
+	conn *net.UnixConn
+	addr *net.UnixAddr
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// NewJournal
+// Создает Journal. Сокет journald не проверяется до первой записи
+func NewJournal(options ...JournalOption) (*Journal, error) {
+	opt := JournalOptions{
+		Socket: JournalSocket,
+	}
+
+	for _, option := range options {
+		opt = option(opt)
+	}
+
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Net: "unixgram"})
+	if err != nil {
+		return nil, fmt.Errorf("listen unixgram: %w", err)
+	}
+
+	return &Journal{
+		conn: conn,
+		addr: &net.UnixAddr{Name: opt.Socket, Net: "unixgram"},
+	}, nil
+}
+
+func (j *Journal) Write(p []byte) (int, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.closed {
+		return 0, ErrClosed
+	}
+
+	_, err := j.conn.WriteToUnix(p, j.addr)
+	if err == nil {
+		return len(p), nil
+	}
+
+	if !errors.Is(err, syscall.EMSGSIZE) && !errors.Is(err, syscall.ENOBUFS) {
+		return 0, fmt.Errorf("write journal: %w", err)
+	}
+
+	if err := j.writeFD(p); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// writeFD
+// Передает запись через запечатанный memfd, как sd_journal_sendv
+func (j *Journal) writeFD(p []byte) error {
+	f, err := memfd(p)
+	if err != nil {
+		return fmt.Errorf("write journal memfd: %w", err)
+	}
+	defer f.Close()
+
+	if _, _, err := j.conn.WriteMsgUnix(nil, syscall.UnixRights(int(f.Fd())), j.addr); err != nil {
+		return fmt.Errorf("write journal memfd: %w", err)
+	}
+
+	return nil
+}
+
+// Close
+// Закрывает сокет, последующие вызовы Write возвращают ErrClosed
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.closed {
+		return nil
+	}
+
+	j.closed = true
+	return j.conn.Close()
+}
@@ -0,0 +1,88 @@
+package sink
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	_mfdCloexec      = 0x1
+	_mfdAllowSealing = 0x2
+
+	_fAddSeals   = 1033
+	_fSealSeal   = 0x1
+	_fSealShrink = 0x2
+	_fSealGrow   = 0x4
+	_fSealWrite  = 0x8
+)
+
+// memfd
+// Создает запечатанный memfd с содержимым p. Если memfd не поддерживается, создает удаленный временный файл в
+// /dev/shm, как sd_journal_sendv
+func memfd(p []byte) (*os.File, error) {
+	f, err := memfdCreate()
+	if errors.Is(err, syscall.ENOSYS) {
+		return tmpfile(p)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := f.Write(p); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	_, _, errno := syscall.Syscall(syscall.SYS_FCNTL, f.Fd(), _fAddSeals,
+		_fSealSeal|_fSealShrink|_fSealGrow|_fSealWrite)
+	if errno != 0 {
+		_ = f.Close()
+		return nil, fmt.Errorf("seal memfd: %w", errno)
+	}
+
+	return f, nil
+}
+
+// memfdCreate
+// Создает memfd с возможностью запечатывания
+func memfdCreate() (*os.File, error) {
+	if _sysMemfdCreate == 0 {
+		return nil, syscall.ENOSYS
+	}
+
+	name, err := syscall.BytePtrFromString("journal")
+	if err != nil {
+		return nil, err
+	}
+
+	fd, _, errno := syscall.Syscall(_sysMemfdCreate, uintptr(unsafe.Pointer(name)), _mfdCloexec|_mfdAllowSealing, 0)
+	if errno != 0 {
+		return nil, fmt.Errorf("memfd_create: %w", errno)
+	}
+
+	return os.NewFile(fd, "journal"), nil
+}
+
+// tmpfile
+// Создает удаленный временный файл с содержимым p
+func tmpfile(p []byte) (*os.File, error) {
+	f, err := os.CreateTemp("/dev/shm", "journal.*")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.Remove(f.Name()); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	if _, err := f.Write(p); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	return f, nil
+}
@@ -0,0 +1,5 @@
+package sink
+
+// _sysMemfdCreate
+// Номер системного вызова memfd_create
+const _sysMemfdCreate = 279
@@ -0,0 +1,7 @@
+//go:build linux && !amd64 && !arm64
+
+package sink
+
+// _sysMemfdCreate
+// Номер системного вызова memfd_create не определен, используется временный файл
+const _sysMemfdCreate = 0
@@ -0,0 +1,14 @@
+//go:build !linux
+
+package sink
+
+import (
+	"errors"
+	"os"
+)
+
+// memfd
+// memfd поддерживается только в Linux
+func memfd([]byte) (*os.File, error) {
+	return nil, errors.ErrUnsupported
+}
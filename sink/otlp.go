@@ -0,0 +1,384 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// DefaultOTLPBatchSize
+	// Максимальное кол-во записей в одном запросе по умолчанию
+	DefaultOTLPBatchSize = 512
+
+	// DefaultOTLPFlushInterval
+	// Интервал отправки накопленных записей по умолчанию
+	DefaultOTLPFlushInterval = time.Second
+
+	// DefaultOTLPQueueSize
+	// Максимальное кол-во записей, ожидающих отправки, по умолчанию
+	DefaultOTLPQueueSize = 2048
+)
+
+// OTLPOptions
+// Настройки OTLP
+type OTLPOptions struct {
+	// Client
+	// HTTP-клиент, по умолчанию - http.Client с таймаутом DefaultHTTPTimeout
+	Client *http.Client
+
+	// Headers
+	// Дополнительные заголовки запроса, например, для авторизации
+	Headers map[string]string
+
+	// Resource
+	// Атрибуты ресурса, по умолчанию - service.name со значением "unknown_service:<исполняемый файл>"
+	Resource map[string]any
+
+	// Compression
+	// Способ сжатия тела запроса, по умолчанию - CompressionNone. CompressionZlib передается как deflate
+	Compression Compression
+
+	// BatchSize
+	// Максимальное кол-во записей в одном запросе, по умолчанию - DefaultOTLPBatchSize
+	BatchSize int
+
+	// FlushInterval
+	// Интервал отправки накопленных записей, по умолчанию - DefaultOTLPFlushInterval
+	FlushInterval time.Duration
+
+	// QueueSize
+	// Максимальное кол-во записей, ожидающих отправки, по умолчанию - DefaultOTLPQueueSize. При заполнении очереди
+	// Write возвращает ErrQueueFull
+	QueueSize int
+
+	// Backoff
+	// Политика повторных попыток отправки, по умолчанию - DefaultBackoff
+	Backoff Backoff
+
+	// CloseTimeout
+	// Время ожидания отправки накопленных записей при Close, по умолчанию - DefaultCloseTimeout. Нулевое значение
+	// отключает ограничение
+	CloseTimeout time.Duration
+
+	// ErrorHandler
+	// Функция, вызываемая при ошибке отправки после исчерпания попыток, по умолчанию - nil
+	ErrorHandler func(err error)
+}
+
+// OTLPOption
+// Опция-функция для настройки OTLP
+type OTLPOption func(o OTLPOptions) OTLPOptions
+
+// WithOTLPClient
+// Определяет HTTP-клиент
+func WithOTLPClient(client *http.Client) OTLPOption {
+	return func(o OTLPOptions) OTLPOptions {
+		if client != nil {
+			o.Client = client
+		}
+
+		return o
+	}
+}
+
+// WithOTLPHeaders
+// Добавляет заголовки запроса
+func WithOTLPHeaders(headers map[string]string) OTLPOption {
+	headers = maps.Clone(headers)
+
+	return func(o OTLPOptions) OTLPOptions {
+		o.Headers = maps.Clone(o.Headers)
+		if o.Headers == nil {
+			o.Headers = make(map[string]string, len(headers))
+		}
+
+		maps.Copy(o.Headers, headers)
+		return o
+	}
+}
+
+// WithOTLPResource
+// Добавляет атрибуты ресурса, например, service.name и service.version
+func WithOTLPResource(attributes map[string]any) OTLPOption {
+	attributes = maps.Clone(attributes)
+
+	return func(o OTLPOptions) OTLPOptions {
+		o.Resource = maps.Clone(o.Resource)
+		if o.Resource == nil {
+			o.Resource = make(map[string]any, len(attributes))
+		}
+
+		maps.Copy(o.Resource, attributes)
+		return o
+	}
+}
+
+// WithOTLPCompression
+// Определяет способ сжатия тела запроса
+func WithOTLPCompression(compression Compression) OTLPOption {
+	return func(o OTLPOptions) OTLPOptions {
+		o.Compression = compression
+		return o
+	}
+}
+
+// WithOTLPBatch
+// Определяет максимальное кол-во записей в одном запросе и интервал отправки накопленных записей
+func WithOTLPBatch(size int, interval time.Duration) OTLPOption {
+	return func(o OTLPOptions) OTLPOptions {
+		if size > 0 {
+			o.BatchSize = size
+		}
+
+		if interval > 0 {
+			o.FlushInterval = interval
+		}
+
+		return o
+	}
+}
+
+// WithOTLPQueueSize
+// Определяет максимальное кол-во записей, ожидающих отправки
+func WithOTLPQueueSize(size int) OTLPOption {
+	return func(o OTLPOptions) OTLPOptions {
+		if size > 0 {
+			o.QueueSize = size
+		}
+
+		return o
+	}
+}
+
+// WithOTLPBackoff
+// Определяет политику повторных попыток отправки
+func WithOTLPBackoff(backoff Backoff) OTLPOption {
+	return func(o OTLPOptions) OTLPOptions {
+		o.Backoff = backoff
+		return o
+	}
+}
+
+// WithOTLPCloseTimeout
+// Определяет время ожидания отправки накопленных записей при Close
+func WithOTLPCloseTimeout(d time.Duration) OTLPOption {
+	return func(o OTLPOptions) OTLPOptions {
+		o.CloseTimeout = d
+		return o
+	}
+}
+
+// WithOTLPErrorHandler
+// Определяет функцию, вызываемую при ошибке отправки после исчерпания попыток
+func WithOTLPErrorHandler(handler func(err error)) OTLPOption {
+	return func(o OTLPOptions) OTLPOptions {
+		o.ErrorHandler = handler
+		return o
+	}
+}
+
+// OTLP
+// Поток вывода, экспортирующий логи в формате log.FormatJSON со схемой log.SchemaOTel в OpenTelemetry Collector по
+// OTLP/HTTP в кодировке JSON. Каждый вызов Write преобразует один лог в LogRecord: SeverityNumber, SeverityText, Body
+// и TraceId/SpanId переносятся в одноименные поля, атрибуты - в AnyValue с сохранением типа, InstrumentationScope -
+// в scope. Записи отправляются пакетами в отдельной горутине с повторными попытками. Close отправляет накопленные
+// записи. Безопасен для конкурентного использования
+type OTLP struct {
+	endpoint string
+	opt      OTLPOptions
+	header   http.Header
+	resource otlpResource
+
+	batch *batcher[otlpRecord]
+}
+
+// NewOTLP
+// Создает OTLP, отправляющий записи на endpoint, например, "http://localhost:4318/v1/logs"
+func NewOTLP(endpoint string, options ...OTLPOption) *OTLP {
+	opt := OTLPOptions{
+		Client:        &http.Client{Timeout: DefaultHTTPTimeout},
+		Resource:      map[string]any{"service.name": "unknown_service:" + filepath.Base(os.Args[0])},
+		Compression:   CompressionNone,
+		BatchSize:     DefaultOTLPBatchSize,
+		FlushInterval: DefaultOTLPFlushInterval,
+		QueueSize:     DefaultOTLPQueueSize,
+		Backoff:       DefaultBackoff,
+		CloseTimeout:  DefaultCloseTimeout,
+	}
+
+	for _, option := range options {
+		opt = option(opt)
+	}
+
+	header := make(http.Header, len(opt.Headers)+2)
+	for k, v := range opt.Headers {
+		header.Set(k, v)
+	}
+
+	header.Set("Content-Type", "application/json")
+
+	o := &OTLP{
+		endpoint: endpoint,
+		opt:      opt,
+		header:   header,
+		resource: otlpResource{Attributes: otlpAttributes(opt.Resource)},
+	}
+
+	o.batch = newBatcher(opt.BatchSize, opt.QueueSize, opt.FlushInterval, o.export, nil)
+
+	return o
+}
+
+func (o *OTLP) Write(p []byte) (int, error) {
+	r, err := parseOTLPRecord(p)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := o.batch.add(r); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Close
+// Отправляет накопленные записи и останавливает отправку, последующие вызовы Write возвращают ErrClosed. Если
+// отправка не завершилась за CloseTimeout, она прерывается, оставшиеся записи отбрасываются с передачей ошибки в
+// ErrorHandler, Close возвращает ErrCloseTimeout
+func (o *OTLP) Close() error {
+	return o.batch.close(o.opt.CloseTimeout)
+}
+
+// export
+// Отправляет пакет записей, сгруппированных по scope
+func (o *OTLP) export(ctx context.Context, records []otlpRecord) {
+	if err := o.send(ctx, records); err != nil && o.opt.ErrorHandler != nil {
+		o.opt.ErrorHandler(fmt.Errorf("export otlp: %w", err))
+	}
+}
+
+func (o *OTLP) send(ctx context.Context, records []otlpRecord) error {
+	var scopes []otlpScopeLogs
+
+	index := make(map[string]int)
+	for _, r := range records {
+		i, ok := index[r.scope]
+		if !ok {
+			i = len(scopes)
+			index[r.scope] = i
+			scopes = append(scopes, otlpScopeLogs{Scope: otlpScope{Name: r.scope}})
+		}
+
+		scopes[i].LogRecords = append(scopes[i].LogRecords, r.record)
+	}
+
+	body, err := json.Marshal(otlpRequest{
+		ResourceLogs: []otlpResourceLogs{{Resource: o.resource, ScopeLogs: scopes}},
+	})
+	if err != nil {
+		return err
+	}
+
+	body, encoding, err := compressBody(o.opt.Compression, body)
+	if err != nil {
+		return err
+	}
+
+	header := o.header
+	if len(encoding) > 0 {
+		header = header.Clone()
+		header.Set("Content-Encoding", encoding)
+	}
+
+	return o.opt.Backoff.do(ctx, func() error {
+		_, err := post(ctx, o.opt.Client, o.endpoint, header, body)
+		return err
+	})
+}
+
+// otlpRecord
+// Запись, ожидающая отправки, с названием scope
+type otlpRecord struct {
+	scope  string
+	record otlpLogRecord
+}
+
+// otelEntry
+// Лог в формате log.FormatJSON со схемой log.SchemaOTel
+type otelEntry struct {
+	Timestamp            string
+	SeverityText         string
+	SeverityNumber       int
+	Body                 any
+	Attributes           map[string]any
+	TraceID              string `json:"TraceId"`
+	SpanID               string `json:"SpanId"`
+	InstrumentationScope struct {
+		Name string
+	}
+}
+
+// parseOTLPRecord
+// Преобразует лог в формате log.FormatJSON со схемой log.SchemaOTel в LogRecord
+func parseOTLPRecord(p []byte) (otlpRecord, error) {
+	dec := json.NewDecoder(bytes.NewReader(p))
+	dec.UseNumber()
+
+	var e otelEntry
+	if err := dec.Decode(&e); err != nil {
+		return otlpRecord{}, fmt.Errorf("parse otlp record: %w", err)
+	}
+
+	now := time.Now()
+
+	r := otlpLogRecord{
+		ObservedTimeUnixNano: uint64(now.UnixNano()), //nolint:gosec // time after 1970
+		SeverityNumber:       e.SeverityNumber,
+		SeverityText:         e.SeverityText,
+		Attributes:           otlpAttributes(e.Attributes),
+	}
+
+	if t, err := time.Parse(time.RFC3339Nano, e.Timestamp); err == nil {
+		r.TimeUnixNano = uint64(t.UnixNano()) //nolint:gosec // time after 1970
+	}
+
+	if e.Body != nil {
+		body := otlpValue(e.Body)
+		r.Body = &body
+	}
+
+	if isHexID(e.TraceID, 16) {
+		r.TraceID = e.TraceID
+	}
+
+	if isHexID(e.SpanID, 8) {
+		r.SpanID = e.SpanID
+	}
+
+	return otlpRecord{scope: e.InstrumentationScope.Name, record: r}, nil
+}
+
+// isHexID
+// Сообщает, что s - идентификатор размером size байт в шестнадцатеричном виде, отличный от нулевого
+func isHexID(s string, size int) bool {
+	if len(s) != size*2 || s == string(bytes.Repeat([]byte{'0'}, size*2)) {
+		return false
+	}
+
+	for i := range len(s) {
+		c := s[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') && (c < 'A' || c > 'F') {
+			return false
+		}
+	}
+
+	return true
+}
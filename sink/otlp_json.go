@@ -0,0 +1,130 @@
+package sink
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Типы запроса OTLP/HTTP в кодировке JSON (opentelemetry/proto/collector/logs/v1)
+
+type otlpRequest struct {
+	ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+}
+
+type otlpResourceLogs struct {
+	Resource  otlpResource    `json:"resource"`
+	ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpScopeLogs struct {
+	Scope      otlpScope       `json:"scope"`
+	LogRecords []otlpLogRecord `json:"logRecords"`
+}
+
+type otlpScope struct {
+	Name string `json:"name,omitempty"`
+}
+
+type otlpLogRecord struct {
+	TimeUnixNano         uint64         `json:"timeUnixNano,omitempty,string"`
+	ObservedTimeUnixNano uint64         `json:"observedTimeUnixNano,string"`
+	SeverityNumber       int            `json:"severityNumber,omitempty"`
+	SeverityText         string         `json:"severityText,omitempty"`
+	Body                 *otlpAnyValue  `json:"body,omitempty"`
+	Attributes           []otlpKeyValue `json:"attributes,omitempty"`
+	TraceID              string         `json:"traceId,omitempty"`
+	SpanID               string         `json:"spanId,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+// otlpAnyValue
+// Значение AnyValue, заполняется ровно одно поле
+type otlpAnyValue struct {
+	StringValue *string           `json:"stringValue,omitempty"`
+	BoolValue   *bool             `json:"boolValue,omitempty"`
+	IntValue    *int64            `json:"intValue,omitempty,string"`
+	DoubleValue *float64          `json:"doubleValue,omitempty"`
+	ArrayValue  *otlpArrayValue   `json:"arrayValue,omitempty"`
+	KvlistValue *otlpKeyValueList `json:"kvlistValue,omitempty"`
+}
+
+type otlpArrayValue struct {
+	Values []otlpAnyValue `json:"values"`
+}
+
+type otlpKeyValueList struct {
+	Values []otlpKeyValue `json:"values"`
+}
+
+// otlpAttributes
+// Преобразует атрибуты в набор KeyValue, упорядоченный по ключам
+func otlpAttributes(attributes map[string]any) []otlpKeyValue {
+	if len(attributes) == 0 {
+		return nil
+	}
+
+	kvs := make([]otlpKeyValue, 0, len(attributes))
+	for k, v := range attributes {
+		kvs = append(kvs, otlpKeyValue{Key: k, Value: otlpValue(v)})
+	}
+
+	slices.SortFunc(kvs, func(a, b otlpKeyValue) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+
+	return kvs
+}
+
+// otlpValue
+// Преобразует значение, полученное из JSON, в AnyValue: целые числа - в intValue, числа с дробной частью - в
+// doubleValue, объекты - в kvlistValue, массивы - в arrayValue
+func otlpValue(v any) otlpAnyValue {
+	switch v := v.(type) {
+	case string:
+		return otlpAnyValue{StringValue: &v}
+	case bool:
+		return otlpAnyValue{BoolValue: &v}
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return otlpAnyValue{IntValue: &i}
+		}
+
+		if f, err := v.Float64(); err == nil {
+			return otlpAnyValue{DoubleValue: &f}
+		}
+
+		s := v.String()
+		return otlpAnyValue{StringValue: &s}
+	case int:
+		i := int64(v)
+		return otlpAnyValue{IntValue: &i}
+	case int64:
+		return otlpAnyValue{IntValue: &v}
+	case float64:
+		return otlpAnyValue{DoubleValue: &v}
+	case []any:
+		values := make([]otlpAnyValue, 0, len(v))
+		for _, item := range v {
+			values = append(values, otlpValue(item))
+		}
+
+		return otlpAnyValue{ArrayValue: &otlpArrayValue{Values: values}}
+	case map[string]any:
+		return otlpAnyValue{KvlistValue: &otlpKeyValueList{Values: otlpAttributes(v)}}
+	case nil:
+		return otlpAnyValue{}
+	default:
+		s := fmt.Sprint(v)
+		return otlpAnyValue{StringValue: &s}
+	}
+}
@@ -0,0 +1,247 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/anticrew/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_OTLP(t *testing.T) {
+	t.Parallel()
+
+	c := &httpReceiver{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "token", r.Header.Get("Authorization"))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		c.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	o := NewOTLP(server.URL+"/v1/logs",
+		WithOTLPHeaders(map[string]string{"Authorization": "token"}),
+		WithOTLPResource(map[string]any{"service.name": "app"}),
+		WithOTLPCompression(CompressionGzip),
+		WithOTLPBatch(10, time.Hour),
+	)
+
+	l := log.NewLogger(log.WithWriter(o), log.WithFormat(log.FormatJSON), log.WithSchema(log.SchemaOTel))
+
+	ctx := log.AddContextArgs(context.Background(),
+		log.String(log.TraceIDKey, "0af7651916cd43dd8448eb211c80319c"),
+		log.String(log.SpanIDKey, "b7ad6b7169203331"),
+	)
+
+	l.Named("svc").Warn(ctx, nil, "hello", log.Int("n", 1), log.Float64("f", 1), log.Bool("ok", true),
+		log.Any("list", []string{"a"}), log.Any("map", map[string]int{"k": 2}))
+	l.Info(log.NoContext, "plain")
+
+	require.NoError(t, o.Close())
+
+	requests := c.get()
+	require.Len(t, requests, 1)
+
+	var req struct {
+		ResourceLogs []struct {
+			Resource  map[string]any
+			ScopeLogs []struct {
+				Scope      map[string]any
+				LogRecords []map[string]any
+			}
+		}
+	}
+
+	require.NoError(t, json.Unmarshal(requests[0], &req))
+
+	require.Len(t, req.ResourceLogs, 1)
+	assert.Equal(t, map[string]any{"attributes": []any{
+		map[string]any{"key": "service.name", "value": map[string]any{"stringValue": "app"}},
+	}}, req.ResourceLogs[0].Resource)
+
+	scopes := req.ResourceLogs[0].ScopeLogs
+	require.Len(t, scopes, 2)
+	assert.Equal(t, map[string]any{"name": "svc"}, scopes[0].Scope)
+	assert.Equal(t, map[string]any{}, scopes[1].Scope)
+
+	require.Len(t, scopes[0].LogRecords, 1)
+	r := scopes[0].LogRecords[0]
+
+	assert.NotEmpty(t, r["timeUnixNano"])
+	assert.NotEmpty(t, r["observedTimeUnixNano"])
+	delete(r, "timeUnixNano")
+	delete(r, "observedTimeUnixNano")
+
+	assert.Equal(t, map[string]any{
+		"severityNumber": float64(13),
+		"severityText":   "WARN",
+		"body":           map[string]any{"stringValue": "hello"},
+		"traceId":        "0af7651916cd43dd8448eb211c80319c",
+		"spanId":         "b7ad6b7169203331",
+		"attributes": []any{
+			map[string]any{"key": "f", "value": map[string]any{"doubleValue": float64(1)}},
+			map[string]any{"key": "list", "value": map[string]any{"arrayValue": map[string]any{"values": []any{
+				map[string]any{"stringValue": "a"},
+			}}}},
+			map[string]any{"key": "map", "value": map[string]any{"kvlistValue": map[string]any{"values": []any{
+				map[string]any{"key": "k", "value": map[string]any{"intValue": "2"}},
+			}}}},
+			map[string]any{"key": "n", "value": map[string]any{"intValue": "1"}},
+			map[string]any{"key": "ok", "value": map[string]any{"boolValue": true}},
+		},
+	}, r)
+
+	require.Len(t, scopes[1].LogRecords, 1)
+	assert.Equal(t, map[string]any{"stringValue": "plain"}, scopes[1].LogRecords[0]["body"])
+	assert.InDelta(t, 9, scopes[1].LogRecords[0]["severityNumber"], 0)
+}
+
+func Test_OTLP_BatchSize(t *testing.T) {
+	t.Parallel()
+
+	c := &httpReceiver{}
+	server := httptest.NewServer(c)
+	defer server.Close()
+
+	o := NewOTLP(server.URL, WithOTLPBatch(2, time.Hour))
+	l := log.NewLogger(log.WithWriter(o), log.WithFormat(log.FormatJSON), log.WithSchema(log.SchemaOTel))
+
+	l.Info(log.NoContext, "first")
+	l.Info(log.NoContext, "second")
+
+	assert.Eventually(t, func() bool { return len(c.get()) == 1 }, time.Second, 10*time.Millisecond)
+
+	l.Info(log.NoContext, "third")
+	require.NoError(t, o.Close())
+
+	assert.Len(t, c.get(), 2)
+
+	_, err := o.Write([]byte(`{}`))
+	require.ErrorIs(t, err, ErrClosed)
+}
+
+func Test_OTLP_Retry(t *testing.T) {
+	t.Parallel()
+
+	var attempts atomic.Int32
+
+	c := &httpReceiver{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		c.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	var errs []error
+
+	o := NewOTLP(server.URL,
+		WithOTLPBackoff(Backoff{Attempts: 3, Initial: time.Millisecond, Max: time.Millisecond}),
+		WithOTLPErrorHandler(func(err error) { errs = append(errs, err) }),
+	)
+
+	_, err := o.Write([]byte(`{"Body":"retried"}`))
+	require.NoError(t, err)
+	require.NoError(t, o.Close())
+
+	assert.Equal(t, int32(3), attempts.Load())
+	assert.Len(t, c.get(), 1)
+	assert.Empty(t, errs)
+}
+
+func Test_OTLP_Error(t *testing.T) {
+	t.Parallel()
+
+	var attempts atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	var errs []error
+
+	o := NewOTLP(server.URL, WithOTLPErrorHandler(func(err error) { errs = append(errs, err) }))
+
+	_, err := o.Write([]byte(`{"Body":"rejected"}`))
+	require.NoError(t, err)
+	require.NoError(t, o.Close())
+
+	assert.Equal(t, int32(1), attempts.Load(), "client errors must not be retried")
+	require.Len(t, errs, 1)
+	require.ErrorIs(t, errs[0], ErrUnexpectedStatus)
+
+	_, err = o.Write([]byte("not json"))
+	require.Error(t, err)
+}
+
+func Test_OTLP_CloseTimeout(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	var errs []error
+
+	o := NewOTLP(server.URL,
+		WithOTLPBackoff(Backoff{Attempts: 10, Initial: time.Hour, Max: time.Hour}),
+		WithOTLPCloseTimeout(50*time.Millisecond),
+		WithOTLPErrorHandler(func(err error) { errs = append(errs, err) }),
+	)
+
+	_, err := o.Write([]byte(`{"Body":"dropped"}`))
+	require.NoError(t, err)
+
+	start := time.Now()
+	require.ErrorIs(t, o.Close(), ErrCloseTimeout)
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	require.Len(t, errs, 1)
+	assert.ErrorIs(t, errs[0], ErrCloseTimeout)
+	assert.ErrorIs(t, errs[0], ErrUnexpectedStatus)
+}
+
+func Test_OTLP_QueueFull(t *testing.T) {
+	t.Parallel()
+
+	block := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		<-block
+	}))
+	defer server.Close()
+
+	o := NewOTLP(server.URL, WithOTLPBatch(1, time.Hour), WithOTLPQueueSize(2))
+
+	_, err := o.Write([]byte(`{"Body":"in flight"}`))
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		o.batch.mu.Lock()
+		defer o.batch.mu.Unlock()
+
+		return len(o.batch.items) == 0
+	}, time.Second, time.Millisecond)
+
+	for range 2 {
+		_, err = o.Write([]byte(`{"Body":"queued"}`))
+		require.NoError(t, err)
+	}
+
+	_, err = o.Write([]byte(`{"Body":"dropped"}`))
+	require.ErrorIs(t, err, ErrQueueFull)
+
+	close(block)
+	require.NoError(t, o.Close())
+}
@@ -0,0 +1,46 @@
+package sink
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// httpReceiver
+// Тестовый сервер, сохраняющий распакованные тела запросов, используется тестами HTTP и OTLP
+type httpReceiver struct {
+	mu     sync.Mutex
+	bodies [][]byte
+}
+
+func (c *httpReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body io.Reader = r.Body
+
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		body = zr
+	}
+
+	b, err := io.ReadAll(body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	c.mu.Lock()
+	c.bodies = append(c.bodies, b)
+	c.mu.Unlock()
+}
+
+func (c *httpReceiver) get() [][]byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.bodies
+}
@@ -0,0 +1,205 @@
+package sink
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// _spoolExt
+	// Расширение файлов пакетов в каталоге буфера
+	_spoolExt = ".batch"
+
+	// _spoolTmpExt
+	// Расширение файлов пакетов, запись которых не завершена
+	_spoolTmpExt = _spoolExt + ".tmp"
+)
+
+// spool
+// Буфер пакетов на диске: каждый пакет хранится в отдельном файле, названия файлов упорядочены по времени записи. При
+// превышении maxBytes удаляются самые старые пакеты. Не безопасен для конкурентного использования
+type spool struct {
+	dir      string
+	maxBytes int64
+	seq      uint64
+
+	// pending
+	// Кол-во пакетов в буфере
+	pending int
+}
+
+func newSpool(dir string, maxBytes int64) (*spool, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create spool: %w", err)
+	}
+
+	s := &spool{
+		dir:      dir,
+		maxBytes: maxBytes,
+	}
+
+	if err := s.clean(); err != nil {
+		return nil, err
+	}
+
+	files, err := s.files()
+	if err != nil {
+		return nil, err
+	}
+
+	s.pending = len(files)
+
+	return s, nil
+}
+
+// save
+// Сохраняет пакет в буфер, удаляя самые старые пакеты при превышении размера буфера
+func (s *spool) save(body []byte) error {
+	s.seq++
+
+	name := filepath.Join(s.dir, fmt.Sprintf("%020d-%06d%s", time.Now().UnixNano(), s.seq%1e6, _spoolExt))
+	if err := s.write(name, body); err != nil {
+		return err
+	}
+
+	s.pending++
+
+	return s.trim()
+}
+
+// write
+// Записывает пакет в файл name через временный файл, чтобы прерванная запись не повредила пакет
+func (s *spool) write(name string, body []byte) error {
+	tmp := strings.TrimSuffix(name, _spoolExt) + _spoolTmpExt
+
+	if err := os.WriteFile(tmp, body, 0o600); err != nil {
+		return fmt.Errorf("save spool: %w", err)
+	}
+
+	if err := os.Rename(tmp, name); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("save spool: %w", err)
+	}
+
+	return nil
+}
+
+// replay
+// Передает пакеты из буфера в send, начиная с самого старого, и удаляет отправленные. Останавливается на первой ошибке,
+// заменяя пакет телом, возвращенным send, если оно отличается от исходного
+func (s *spool) replay(send func(body []byte) ([]byte, error)) error {
+	files, err := s.files()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range files {
+		body, err := os.ReadFile(name)
+		if err != nil {
+			return fmt.Errorf("read spool: %w", err)
+		}
+
+		rest, err := send(body)
+		if err != nil {
+			if !bytes.Equal(rest, body) {
+				if werr := s.write(name, rest); werr != nil {
+					return errors.Join(err, werr)
+				}
+			}
+
+			return err
+		}
+
+		if err := os.Remove(name); err != nil {
+			return fmt.Errorf("remove spool: %w", err)
+		}
+
+		s.pending--
+	}
+
+	s.pending = 0
+
+	return nil
+}
+
+// trim
+// Удаляет самые старые пакеты, пока размер буфера превышает maxBytes
+func (s *spool) trim() error {
+	if s.maxBytes <= 0 {
+		return nil
+	}
+
+	files, err := s.files()
+	if err != nil {
+		return err
+	}
+
+	var (
+		total int64
+		sizes = make([]int64, len(files))
+	)
+
+	for i, name := range files {
+		info, err := os.Stat(name)
+		if err != nil {
+			continue
+		}
+
+		sizes[i] = info.Size()
+		total += sizes[i]
+	}
+
+	for i := 0; total > s.maxBytes && i < len(files); i++ {
+		if err := os.Remove(files[i]); err != nil {
+			return fmt.Errorf("trim spool: %w", err)
+		}
+
+		total -= sizes[i]
+		s.pending--
+	}
+
+	return nil
+}
+
+// clean
+// Удаляет файлы пакетов, запись которых была прервана завершением процесса
+func (s *spool) clean() error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return fmt.Errorf("read spool: %w", err)
+	}
+
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), _spoolTmpExt) {
+			if err := os.Remove(filepath.Join(s.dir, e.Name())); err != nil {
+				return fmt.Errorf("clean spool: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// files
+// Возвращает пути к файлам пакетов, начиная с самого старого
+func (s *spool) files() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("read spool: %w", err)
+	}
+
+	// os.ReadDir упорядочивает файлы по названию, то есть по времени записи
+	files := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), _spoolExt) {
+			files = append(files, filepath.Join(s.dir, e.Name()))
+		}
+	}
+
+	return files, nil
+}
@@ -0,0 +1,229 @@
+package sink
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Framing
+// Способ разделения сообщений syslog в потоковых соединениях (RFC 6587)
+type Framing int
+
+const (
+	// FramingOctetCounting
+	// Каждое сообщение предваряется длиной в байтах и пробелом: "LEN MSG"
+	FramingOctetCounting Framing = iota
+
+	// FramingNewline
+	// Каждое сообщение завершается переводом строки
+	FramingNewline
+)
+
+const (
+	// DefaultDialTimeout
+	// Время ожидания подключения по умолчанию
+	DefaultDialTimeout = 5 * time.Second
+
+	// DefaultWriteTimeout
+	// Время ожидания записи в соединение по умолчанию
+	DefaultWriteTimeout = DefaultHTTPTimeout
+)
+
+// SyslogOptions
+// Настройки Syslog
+type SyslogOptions struct {
+	// Framing
+	// Способ разделения сообщений в потоковых соединениях (tcp, unix), по умолчанию - FramingOctetCounting. В
+	// датаграммных соединениях (udp, unixgram) каждое сообщение отправляется отдельной датаграммой
+	Framing Framing
+
+	// DialTimeout
+	// Время ожидания подключения, по умолчанию - DefaultDialTimeout
+	DialTimeout time.Duration
+
+	// WriteTimeout
+	// Время ожидания записи сообщения, по умолчанию - DefaultWriteTimeout. Нулевое значение отключает ограничение
+	WriteTimeout time.Duration
+}
+
+// SyslogOption
+// Опция-функция для настройки Syslog
+type SyslogOption func(o SyslogOptions) SyslogOptions
+
+// WithFraming
+// Определяет способ разделения сообщений в потоковых соединениях
+func WithFraming(framing Framing) SyslogOption {
+	return func(o SyslogOptions) SyslogOptions {
+		o.Framing = framing
+		return o
+	}
+}
+
+// WithDialTimeout
+// Определяет время ожидания подключения
+func WithDialTimeout(d time.Duration) SyslogOption {
+	return func(o SyslogOptions) SyslogOptions {
+		o.DialTimeout = d
+		return o
+	}
+}
+
+// WithWriteTimeout
+// Определяет время ожидания записи сообщения, по истечении которого соединение устанавливается заново
+func WithWriteTimeout(d time.Duration) SyslogOption {
+	return func(o SyslogOptions) SyslogOptions {
+		o.WriteTimeout = d
+		return o
+	}
+}
+
+// Syslog
+// Поток вывода, отправляющий логи в форматах log.FormatSyslog5424 и log.FormatSyslog3164 на сервер syslog по UDP, TCP
+// или unix-сокету. Каждый вызов Write отправляет одно сообщение, завершающий перевод строки удаляется. При ошибке
+// записи соединение устанавливается заново и запись повторяется один раз. Безопасен для конкурентного использования
+type Syslog struct {
+	network string
+	addr    string
+	stream  bool
+	opt     SyslogOptions
+
+	mu     sync.Mutex
+	conn   net.Conn
+	buf    []byte
+	closed bool
+}
+
+// NewSyslog
+// Создает Syslog и подключается к серверу. Поддерживаемые типы сети: udp, udp4, udp6, tcp, tcp4, tcp6, unix и
+// unixgram
+func NewSyslog(network, addr string, options ...SyslogOption) (*Syslog, error) {
+	var stream bool
+
+	switch network {
+	case "tcp", "tcp4", "tcp6", "unix":
+		stream = true
+	case "udp", "udp4", "udp6", "unixgram":
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedNetwork, network)
+	}
+
+	opt := SyslogOptions{
+		Framing:      FramingOctetCounting,
+		DialTimeout:  DefaultDialTimeout,
+		WriteTimeout: DefaultWriteTimeout,
+	}
+
+	for _, option := range options {
+		opt = option(opt)
+	}
+
+	s := &Syslog{
+		network: network,
+		addr:    addr,
+		stream:  stream,
+		opt:     opt,
+	}
+
+	if err := s.dial(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *Syslog) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return 0, ErrClosed
+	}
+
+	s.buf = s.frame(s.buf[:0], bytes.TrimSuffix(p, []byte{'\n'}))
+
+	if s.conn != nil {
+		if _, err := writeDeadline(s.conn, s.buf, s.opt.WriteTimeout); err == nil {
+			return len(p), nil
+		}
+
+		_ = s.conn.Close()
+		s.conn = nil
+	}
+
+	if err := s.dial(); err != nil {
+		return 0, err
+	}
+
+	if _, err := writeDeadline(s.conn, s.buf, s.opt.WriteTimeout); err != nil {
+		_ = s.conn.Close()
+		s.conn = nil
+
+		return 0, fmt.Errorf("write %s %s: %w", s.network, s.addr, err)
+	}
+
+	return len(p), nil
+}
+
+// Close
+// Закрывает соединение, последующие вызовы Write возвращают ErrClosed
+func (s *Syslog) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+
+	if s.conn == nil {
+		return nil
+	}
+
+	err := s.conn.Close()
+	s.conn = nil
+
+	return err
+}
+
+// frame
+// Добавляет сообщение в dst с учетом способа разделения сообщений
+func (s *Syslog) frame(dst, msg []byte) []byte {
+	if !s.stream {
+		return append(dst, msg...)
+	}
+
+	if s.opt.Framing == FramingNewline {
+		dst = append(dst, msg...)
+		return append(dst, '\n')
+	}
+
+	dst = strconv.AppendInt(dst, int64(len(msg)), 10)
+	dst = append(dst, ' ')
+
+	return append(dst, msg...)
+}
+
+// dial
+// Устанавливает соединение с сервером
+func (s *Syslog) dial() error {
+	conn, err := net.DialTimeout(s.network, s.addr, s.opt.DialTimeout)
+	if err != nil {
+		return fmt.Errorf("dial %s %s: %w", s.network, s.addr, err)
+	}
+
+	s.conn = conn
+	return nil
+}
+
+// writeDeadline
+// Записывает p в соединение, ограничивая время записи timeout, если он указан
+func writeDeadline(conn net.Conn, p []byte, timeout time.Duration) (int, error) {
+	if timeout > 0 {
+		if err := conn.SetWriteDeadline(time.Now().Add(timeout)); err != nil {
+			return 0, err
+		}
+	}
+
+	return conn.Write(p)
+}
@@ -0,0 +1,185 @@
+package sink
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anticrew/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readOctetCounted
+// Читает сообщение, разделенное с помощью FramingOctetCounting
+func readOctetCounted(t *testing.T, r *bufio.Reader) string {
+	t.Helper()
+
+	size, err := r.ReadString(' ')
+	require.NoError(t, err)
+
+	n, err := strconv.Atoi(strings.TrimSuffix(size, " "))
+	require.NoError(t, err)
+
+	msg := make([]byte, n)
+	_, err = io.ReadFull(r, msg)
+	require.NoError(t, err)
+
+	return string(msg)
+}
+
+func Test_Syslog_UDP(t *testing.T) {
+	t.Parallel()
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer pc.Close()
+
+	s, err := NewSyslog("udp", pc.LocalAddr().String())
+	require.NoError(t, err)
+	defer s.Close()
+
+	l := log.NewLogger(log.WithWriter(s), log.WithFormat(log.FormatSyslog5424), log.WithSyslog(log.FacilityLocal0, "app"))
+	l.Info(log.NoContext, "hello", log.String("key", "value"))
+
+	buf := make([]byte, 1024)
+	require.NoError(t, pc.SetReadDeadline(time.Now().Add(time.Second)))
+
+	n, _, err := pc.ReadFrom(buf)
+	require.NoError(t, err)
+
+	msg := string(buf[:n])
+	assert.True(t, strings.HasPrefix(msg, "<134>1 "), msg)
+	assert.True(t, strings.HasSuffix(msg, ` app `+strconv.Itoa(os.Getpid())+` - [log@32473 key="value"] hello`), msg)
+}
+
+func Test_Syslog_TCP(t *testing.T) {
+	t.Parallel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	conns := make(chan net.Conn, 2)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+
+			conns <- conn
+		}
+	}()
+
+	s, err := NewSyslog("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	defer s.Close()
+
+	first := <-conns
+
+	_, err = s.Write([]byte("<14>first\n"))
+	require.NoError(t, err)
+	_, err = s.Write([]byte("<14>second"))
+	require.NoError(t, err)
+
+	r := bufio.NewReader(first)
+	assert.Equal(t, "<14>first", readOctetCounted(t, r))
+	assert.Equal(t, "<14>second", readOctetCounted(t, r))
+
+	require.NoError(t, first.Close())
+
+	// the first write after the server closed connection may succeed, reconnection happens on the failed one
+	var second net.Conn
+	require.Eventually(t, func() bool {
+		_, _ = s.Write([]byte("<14>reconnected"))
+
+		select {
+		case second = <-conns:
+			return true
+		default:
+			return false
+		}
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, "<14>reconnected", readOctetCounted(t, bufio.NewReader(second)))
+	require.NoError(t, second.Close())
+
+	require.NoError(t, s.Close())
+	_, err = s.Write([]byte("closed"))
+	require.ErrorIs(t, err, ErrClosed)
+}
+
+// writeStalled
+// Записывает в w сообщение, превышающее буферы соединения, и возвращает ошибку записи
+func writeStalled(t *testing.T, w io.Writer) error {
+	t.Helper()
+
+	_, err := w.Write([]byte(strings.Repeat("x", 64<<20)))
+	return err
+}
+
+func Test_Syslog_WriteTimeout(t *testing.T) {
+	t.Parallel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	s, err := NewSyslog("tcp", ln.Addr().String(), WithWriteTimeout(50*time.Millisecond))
+	require.NoError(t, err)
+	defer s.Close()
+
+	start := time.Now()
+	require.ErrorIs(t, writeStalled(t, s), os.ErrDeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func Test_Syslog_Unix(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "syslog.sock")
+
+	ln, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	defer ln.Close()
+
+	s, err := NewSyslog("unix", path, WithFraming(FramingNewline))
+	require.NoError(t, err)
+	defer s.Close()
+
+	conn, err := ln.Accept()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	l := log.NewLogger(log.WithWriter(s), log.WithFormat(log.FormatSyslog3164), log.WithSyslog(log.FacilityDaemon, "app"))
+	l.Error(log.NoContext, nil, "first")
+	l.Warn(log.NoContext, nil, "second")
+
+	r := bufio.NewReader(conn)
+
+	line, err := r.ReadString('\n')
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(line, "<27>"), line)
+	assert.True(t, strings.HasSuffix(line, "]: first\n"), line)
+
+	line, err = r.ReadString('\n')
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(line, "<28>"), line)
+}
+
+func Test_NewSyslog_Error(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewSyslog("ip", "127.0.0.1")
+	require.ErrorIs(t, err, ErrUnsupportedNetwork)
+
+	_, err = NewSyslog("unix", filepath.Join(t.TempDir(), "missing.sock"))
+	require.Error(t, err)
+}
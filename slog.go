@@ -6,6 +6,8 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/anticrew/log/internal/caller"
@@ -13,16 +15,6 @@ import (
 	"github.com/anticrew/go-x/pool"
 )
 
-type Level = slog.Level
-
-const (
-	LevelTrace = slog.LevelDebug - 4
-	LevelDebug = slog.LevelDebug
-	LevelInfo  = slog.LevelInfo
-	LevelWarn  = slog.LevelWarn
-	LevelError = slog.LevelError
-)
-
 type Arg = slog.Attr
 
 func Err(err error) Arg {
@@ -78,26 +70,105 @@ func Any(key string, value any) Arg {
 	return slog.Any(key, value)
 }
 
+// ArgKey
+// Возвращает ключ аргумента
+func ArgKey(a Arg) string {
+	return a.Key
+}
+
+// ArgValue
+// Возвращает значение аргумента, вычисляя ленивые значения. Для аргументов, созданных LazyArgs, возвращает []Arg
+func ArgValue(a Arg) any {
+	v := a.Value.Resolve()
+	if v.Kind() == slog.KindGroup {
+		return v.Group()
+	}
+
+	return v.Any()
+}
+
+// Lazy
+// Создает аргумент, значение которого вычисляется с помощью fn только при записи лога
+func Lazy(key string, fn func() any) Arg {
+	return slog.Any(key, lazyValue(fn))
+}
+
+// LazyArgs
+// Создает аргумент, раскрывающийся в набор аргументов, вычисляемый с помощью fn только при записи лога
+func LazyArgs(fn func() []Arg) Arg {
+	return slog.Any("", lazyArgs(fn))
+}
+
+// lazyValue
+// Значение аргумента, вычисляемое обработчиком slog только при записи лога
+type lazyValue func() any
+
+func (f lazyValue) LogValue() slog.Value {
+	return slog.AnyValue(f())
+}
+
+// lazyArgs
+// Набор аргументов, вычисляемый обработчиком slog только при записи лога. Группа с пустым ключом встраивается в лог
+type lazyArgs func() []Arg
+
+func (f lazyArgs) LogValue() slog.Value {
+	return slog.GroupValue(f()...)
+}
+
 type logger struct {
 	log *slog.Logger
 
 	levels *levelsConfig
 
 	opt Options
+
+	// args
+	// Аргументы Logger. При политике обработки повторяющихся ключей DuplicateKeysKeep добавляются в log с помощью
+	// slog.Logger.With, иначе обрабатываются при каждой записи лога. Используются для пересоздания в WithOptions
+	args []Arg
+
+	// reserved
+	// Зарезервированные ключи для обработки повторяющихся ключей
+	reserved []string
+
+	// bound
+	// Набор аргументов context.Context, добавленный с помощью WithContext
+	bound BoundContext
+
+	// callerSkip
+	// Кол-во вызовов для пропуска, добавленное оберткой над Logger, например, ContextLogger
+	callerSkip int
+
+	// name
+	// Полное имя Logger, заданное с помощью Named
+	name string
+
+	// capture
+	// Поток вывода, сохраняющий записанные байты для WriteHook, nil без WriteHook
+	capture *captureWriter
 }
 
 func NewLogger(options ...Option) Logger {
 	return createFromOptions(defaultOptions(), options)
 }
 
-func createFromOptions(opt Options, options []Option) Logger {
-	opt = optionChain(options).apply(opt)
+func createFromOptions(opt Options, options []Option) *logger {
+	opt = formatOptions(optionChain(options).apply(opt))
 
-	config := newLevelsConfig(opt.LevelKey, opt.Level)
+	config := newLevelsConfig(opt.LevelKey, opt.Level, opt.LevelNames)
 	handlerOpt := &slog.HandlerOptions{
 		AddSource: false, // always false, we handle source manually
-		Level:     opt.Level,
-		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+		Level:     slog.Level(opt.Level),
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) > 0 {
+				return a
+			}
+
+			if key, ok := strings.CutPrefix(a.Key, _escapePrefix); ok {
+				a.Key = key
+				return a
+			}
+
 			switch a.Key {
 			case slog.LevelKey:
 				return config.replaceValue(a)
@@ -114,20 +185,31 @@ func createFromOptions(opt Options, options []Option) Logger {
 		},
 	}
 
+	out := opt.output()
+
+	capture := newCaptureWriter(opt, out)
+	if capture != nil {
+		out = capture
+	}
+
 	var handler slog.Handler
-	switch opt.Format {
-	case FormatText, FormatLogFmt:
-		handler = slog.NewTextHandler(opt.Writer, handlerOpt)
-	case FormatJSON:
-		handler = slog.NewJSONHandler(opt.Writer, handlerOpt)
+	switch {
+	case opt.encodesParams():
+		handler = newParamsHandler(out, opt)
+	case opt.Format == FormatJSON:
+		handler = slog.NewJSONHandler(out, handlerOpt)
+	default:
+		handler = slog.NewTextHandler(out, handlerOpt)
 	}
 
 	l := slog.New(handler)
 
 	return &logger{
-		log:    l,
-		levels: config,
-		opt:    opt,
+		log:      l,
+		levels:   config,
+		opt:      opt,
+		reserved: reservedKeys(opt, false),
+		capture:  capture,
 	}
 }
 
@@ -138,26 +220,80 @@ var _anyPool = pool.NewPool(func() []any {
 }))
 
 func (l *logger) WithArgs(args ...Arg) Logger {
-	a := _anyPool.Get()
-	defer _anyPool.Put(a)
+	return l.withArgs(args)
+}
 
-	for _, arg := range args {
-		a = append(a, arg)
+func (l *logger) withArgs(args []Arg) *logger {
+	if len(args) == 0 {
+		return l
 	}
 
-	return &logger{
-		log:    l.log.With(a...),
-		levels: l.levels,
-		opt:    l.opt,
+	nl := *l
+	nl.args = append(slices.Clip(l.args), args...)
+
+	if !l.opt.mergeArgs() {
+		a := _anyPool.Get()
+		defer _anyPool.Put(a)
+
+		for _, arg := range args {
+			a = append(a, escapeArg(arg))
+		}
+
+		nl.log = l.log.With(a...)
 	}
+
+	return &nl
 }
 
 func (l *logger) WithContext(ctx context.Context) Logger {
-	return l.WithArgs(GetContextArgs(ctx)...)
+	bound, args, ok := l.bound.Bind(ctx)
+	if !ok {
+		return l
+	}
+
+	nl := *l.withArgs(args)
+	nl.bound = bound
+
+	return &nl
 }
 
 func (l *logger) WithOptions(options ...Option) Logger {
-	return createFromOptions(l.opt, options)
+	nl := *createFromOptions(l.opt, options).withArgs(l.args)
+	nl.bound = l.bound
+	nl.callerSkip = l.callerSkip
+
+	if len(l.name) > 0 {
+		return nl.withName(l.name)
+	}
+
+	return &nl
+}
+
+func (l *logger) Named(name string) Logger {
+	if len(name) == 0 {
+		return l
+	}
+
+	return l.withName(joinName(l.name, name))
+}
+
+func (l *logger) withName(name string) *logger {
+	nl := *l
+	nl.name = name
+	nl.reserved = reservedKeys(l.opt, true)
+
+	return &nl
+}
+
+func (l *logger) WithCallerSkip(skip int) Logger {
+	nl := *l
+	nl.callerSkip += skip
+
+	return &nl
+}
+
+func (l *logger) Sugar() SugaredLogger {
+	return NewSugaredLogger(l)
 }
 
 func (l *logger) Trace(ctx context.Context, msg string, args ...Arg) {
@@ -190,57 +326,164 @@ var _argsPool = pool.NewPool(func() []Arg {
 	return args[:0]
 }))
 
+func (l *logger) Enabled(_ context.Context, level Level) bool {
+	return level >= l.levels.enabled
+}
+
+func (l *logger) Close() error {
+	return l.opt.close()
+}
+
 func (l *logger) logAttrs(ctx context.Context, level Level, err error, msg string, args []Arg) {
-	if l.levels.enabled < level {
+	if !l.Enabled(ctx, level) {
 		return
 	}
 
 	newArgs := _argsPool.Get()
 	defer _argsPool.Put(newArgs)
 
-	if ctx != NoContext {
-		newArgs = append(newArgs, GetContextArgs(ctx)...)
+	if l.opt.mergeArgs() {
+		newArgs = append(newArgs, l.args...)
 	}
 
+	newArgs = l.bound.AppendArgs(newArgs, ctx)
+
 	newArgs = append(newArgs, args...)
 
 	if err != nil {
-		newArgs = append(newArgs, Err(err))
+		newArgs = append(newArgs, l.opt.errorArg(err))
+	}
+
+	newArgs = dedupeArgs(l.opt.DuplicateKeys, l.reserved, newArgs)
+
+	if l.opt.hooked() {
+		var frame *caller.Frame
+		if l.opt.AddSource {
+			frame, _ = caller.Lookup(l.opt.Skip + l.callerSkip + 2) // skip logAttrs and level-dependent function
+		}
+
+		l.logEntry(ctx, newEntry(l.opt, level, msg, l.name, newArgs, frame))
+		return
+	}
+
+	for i := range newArgs {
+		newArgs[i] = escapeArg(newArgs[i])
+	}
+
+	if len(l.name) > 0 && len(l.opt.NameKey) > 0 {
+		newArgs = append(newArgs, escapeArg(slog.String(l.opt.NameKey, l.name)))
 	}
 
 	if l.opt.AddSource {
 		newArgs = append(newArgs, l.getSourceArg(2))
 	}
 
-	l.log.LogAttrs(ctx, level, msg, newArgs...)
+	r := slog.NewRecord(time.Now(), slog.Level(level), msg, 0)
+	r.AddAttrs(newArgs...)
+
+	l.opt.report(level, l.name, l.log.Handler().Handle(ctx, r))
+}
+
+// logEntry
+// Вызывает Hook и записывает Entry напрямую в slog.Handler, чтобы учесть изменения, внесенные Hook
+func (l *logger) logEntry(ctx context.Context, e *Entry) {
+	defer releaseEntry(e)
+
+	if !runHooks(ctx, l.opt.Hooks, e) {
+		l.opt.dropped(e.Level, e.Name)
+		return
+	}
+
+	if !l.log.Handler().Enabled(ctx, slog.Level(e.Level)) {
+		return
+	}
+
+	attrs := _argsPool.Get()
+	defer _argsPool.Put(attrs)
+
+	for _, arg := range e.Args {
+		attrs = append(attrs, escapeArg(arg))
+	}
+
+	if len(e.Name) > 0 && len(l.opt.NameKey) > 0 {
+		attrs = append(attrs, escapeArg(slog.String(l.opt.NameKey, e.Name)))
+	}
+
+	if f := e.sourceFrame(); f != nil && l.opt.AddSource {
+		attrs = append(attrs, l.sourceArg(f))
+	}
+
+	r := slog.NewRecord(e.Time, slog.Level(e.Level), e.Message, 0)
+	r.AddAttrs(attrs...)
+
+	if l.capture == nil {
+		l.opt.report(e.Level, e.Name, l.log.Handler().Handle(ctx, r))
+		return
+	}
+
+	buf := l.capture.begin()
+	err := l.log.Handler().Handle(ctx, r)
+	l.capture.end()
+
+	l.opt.report(e.Level, e.Name, err)
+
+	runWriteHooks(ctx, l.opt.WriteHooks, e, buf.Bytes())
+	buf.Dispose()
 }
 
 func (l *logger) getSourceArg(skip int) Arg {
-	src, err := caller.Take(l.opt.Skip + skip + 1)
-	if err == nil {
-		return slog.String(l.opt.SourceKey, src)
+	c, err := caller.Lookup(l.opt.Skip + l.callerSkip + skip + 1)
+	if err != nil {
+		return slog.String(l.opt.SourceKey, fmt.Sprintf("(error = %v)", err))
+	}
+
+	return l.sourceArg(c)
+}
+
+func (l *logger) sourceArg(c *caller.Frame) Arg {
+	if l.opt.SourceFormat == SourceFormatObject {
+		return slog.Group(l.opt.SourceKey,
+			slog.String(SourceFileKey, c.File),
+			slog.Int(SourceLineKey, c.Line),
+			slog.String(SourceFunctionKey, c.Function),
+		)
+	}
+
+	return slog.String(l.opt.SourceKey, c.String(l.opt.SourceFormat.callerFormat()))
+}
+
+// _escapePrefix
+// Префикс ключей аргументов, совпадающих с ключами встроенных атрибутов slog. Без него ReplaceAttr не может отличить
+// аргумент от встроенного атрибута
+const _escapePrefix = "\x00"
+
+// escapeArg
+// Добавляет _escapePrefix к ключу аргумента, если он совпадает с ключом встроенного атрибута slog
+func escapeArg(a Arg) Arg {
+	switch a.Key {
+	case slog.TimeKey, slog.LevelKey, slog.MessageKey, slog.SourceKey:
+		a.Key = _escapePrefix + a.Key
 	}
 
-	return slog.String(l.opt.SourceKey, fmt.Sprintf("(error = %v)", err))
+	return a
+}
+
+func renameArg(a Arg, key string) Arg {
+	a.Key = key
+	return a
 }
 
 type levelsConfig struct {
 	key     string
 	enabled Level
-	values  map[Level]slog.Value
+	names   map[Level]string
 }
 
-func newLevelsConfig(key string, enabled Level) *levelsConfig {
+func newLevelsConfig(key string, enabled Level, names map[Level]string) *levelsConfig {
 	return &levelsConfig{
 		key:     key,
 		enabled: enabled,
-		values: map[Level]slog.Value{
-			LevelTrace: slog.StringValue(_traceValue),
-			LevelDebug: slog.StringValue(_debugValue),
-			LevelInfo:  slog.StringValue(_infoValue),
-			LevelWarn:  slog.StringValue(_warnValue),
-			LevelError: slog.StringValue(_errorValue),
-		},
+		names:   names,
 	}
 }
 
@@ -251,11 +494,7 @@ func (c *levelsConfig) replaceValue(a slog.Attr) slog.Attr {
 	}
 
 	a.Key = c.key
-
-	var value slog.Value
-	if value, ok = c.values[level]; ok {
-		a.Value = value
-	}
+	a.Value = slog.StringValue(formatLevel(Level(level), c.names))
 
 	return a
 }
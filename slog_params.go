@@ -0,0 +1,150 @@
+//go:build anticrew_log_slog
+
+package log
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/anticrew/go-x/pool"
+	"github.com/anticrew/go-x/xio"
+)
+
+var _paramsPool = pool.NewPool(func() []param {
+	return make([]param, 0, 16)
+})
+
+// paramsHandler
+// Реализация slog.Handler для форматов, записываемых общим для драйверов paramsEncoder
+type paramsHandler struct {
+	enc   paramsEncoder
+	level slog.Leveler
+
+	// attrs
+	// Аргументы, добавленные с помощью WithAttrs
+	attrs []param
+
+	// prefix
+	// Префикс ключей аргументов из групп, открытых с помощью WithGroup
+	prefix string
+
+	mu  *sync.Mutex
+	out io.Writer
+}
+
+func newParamsHandler(out io.Writer, opt Options) *paramsHandler {
+	return &paramsHandler{
+		enc:   newParamsEncoder(opt),
+		level: slog.Level(opt.Level),
+		mu:    &sync.Mutex{},
+		out:   out,
+	}
+}
+
+func (h *paramsHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *paramsHandler) Handle(_ context.Context, r slog.Record) error {
+	params := _paramsPool.Get()
+	defer func() {
+		clear(params)
+		_paramsPool.Put(params[:0])
+	}()
+
+	params = append(params, h.attrs...)
+
+	r.Attrs(func(a slog.Attr) bool {
+		params = appendParamAttr(params, h.prefix, a)
+		return true
+	})
+
+	buf := xio.NewBuffer()
+	defer buf.Dispose()
+
+	h.enc.encode(buf, Level(r.Level), r.Time, r.Message, params)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	_, err := h.out.Write(buf.Bytes())
+	return err
+}
+
+func (h *paramsHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	nh := *h
+	nh.attrs = slices.Clip(h.attrs)
+
+	for _, a := range attrs {
+		nh.attrs = appendParamAttr(nh.attrs, h.prefix, a)
+	}
+
+	return &nh
+}
+
+func (h *paramsHandler) WithGroup(name string) slog.Handler {
+	if len(name) == 0 {
+		return h
+	}
+
+	nh := *h
+	nh.prefix = h.prefix + name + "."
+
+	return &nh
+}
+
+// appendParamAttr
+// Добавляет аргумент в params: аргументы группы с пустым ключом встраиваются, остальные группы записываются в JSON
+func appendParamAttr(params []param, prefix string, a slog.Attr) []param {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return params
+	}
+
+	if a.Value.Kind() == slog.KindGroup && len(a.Key) == 0 {
+		for _, ga := range a.Value.Group() {
+			params = appendParamAttr(params, prefix, ga)
+		}
+
+		return params
+	}
+
+	key, _ := strings.CutPrefix(a.Key, _escapePrefix)
+
+	return append(params, param{
+		key:   prefix + key,
+		value: paramAttrValue(a.Value),
+	})
+}
+
+// paramAttrValue
+// Возвращает значение аргумента, группы преобразуются в map[string]any
+func paramAttrValue(v slog.Value) any {
+	v = v.Resolve()
+	if v.Kind() != slog.KindGroup {
+		return v.Any()
+	}
+
+	m := make(map[string]any, len(v.Group()))
+	for _, a := range v.Group() {
+		if len(a.Key) == 0 && a.Value.Resolve().Kind() == slog.KindGroup {
+			for k, gv := range paramAttrValue(a.Value).(map[string]any) { //nolint:forcetypeassert // group value
+				m[k] = gv
+			}
+
+			continue
+		}
+
+		m[a.Key] = paramAttrValue(a.Value)
+	}
+
+	return m
+}
@@ -0,0 +1,96 @@
+package log
+
+import (
+	"fmt"
+
+	"github.com/anticrew/log/internal/caller"
+)
+
+const (
+	// SourceFileKey
+	// Ключ полного пути к файлу источника в формате SourceFormatObject
+	SourceFileKey = "file"
+
+	// SourceLineKey
+	// Ключ номера строки источника в формате SourceFormatObject
+	SourceLineKey = "line"
+
+	// SourceFunctionKey
+	// Ключ полного названия функции источника в формате SourceFormatObject
+	SourceFunctionKey = "function"
+)
+
+// SourceFormat
+// Формат записи источника лога, одинаковый для всех драйверов
+type SourceFormat uint
+
+const (
+	// SourceFormatShort
+	// Файл, сокращенный до названия пакета, строка и функция: "api/handler.go:42 github.com/org/app/api.Handle".
+	// Формат по умолчанию
+	SourceFormatShort SourceFormat = iota
+
+	// SourceFormatFull
+	// Полный путь к файлу, строка и функция: "/src/app/api/handler.go:42 github.com/org/app/api.Handle"
+	SourceFormatFull
+
+	// SourceFormatFileLine
+	// Файл, сокращенный до названия пакета, и строка: "api/handler.go:42"
+	SourceFormatFileLine
+
+	// SourceFormatFunction
+	// Только полное название функции: "github.com/org/app/api.Handle"
+	SourceFormatFunction
+
+	// SourceFormatModule
+	// Путь к файлу относительно корня модуля, строка и функция:
+	// "internal/api/handler.go:42 github.com/org/app/internal/api.Handle"
+	SourceFormatModule
+
+	// SourceFormatObject
+	// Структурированный объект с полным путем к файлу, строкой и функцией: {"file": ..., "line": ..., "function": ...}.
+	// В текстовых форматах записывается как группа аргументов: "source.file=... source.line=..."
+	SourceFormatObject
+)
+
+func (f SourceFormat) String() string {
+	switch f {
+	case SourceFormatShort:
+		return "short"
+	case SourceFormatFull:
+		return "full"
+	case SourceFormatFileLine:
+		return "file-line"
+	case SourceFormatFunction:
+		return "function"
+	case SourceFormatModule:
+		return "module"
+	case SourceFormatObject:
+		return "object"
+	default:
+		return fmt.Sprintf("SourceFormat<%d>", f)
+	}
+}
+
+func (f SourceFormat) IsValid() bool {
+	return f >= SourceFormatShort && f <= SourceFormatObject
+}
+
+// WithSourceFormat
+// Определяет формат записи источника. Источник записывается только при включении с помощью WithSource
+func WithSourceFormat(format SourceFormat) Option {
+	if !format.IsValid() {
+		return emptyOption
+	}
+
+	return func(o Options) Options {
+		o.SourceFormat = format
+		return o
+	}
+}
+
+// callerFormat
+// Возвращает строковый формат internal/caller для SourceFormat. Значения строковых форматов совпадают
+func (f SourceFormat) callerFormat() caller.Format {
+	return caller.Format(f)
+}
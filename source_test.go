@@ -0,0 +1,109 @@
+package log
+
+import (
+	"bytes"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WithSourceFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		format   SourceFormat
+		expected func(file string, line int, function string) string
+	}{
+		{
+			name:   "short",
+			format: SourceFormatShort,
+			expected: func(file string, line int, function string) string {
+				return shortPath(file) + ":" + strconv.Itoa(line) + " " + function
+			},
+		},
+		{
+			name:   "full",
+			format: SourceFormatFull,
+			expected: func(file string, line int, function string) string {
+				return file + ":" + strconv.Itoa(line) + " " + function
+			},
+		},
+		{
+			name:   "file-line",
+			format: SourceFormatFileLine,
+			expected: func(file string, line int, _ string) string {
+				return shortPath(file) + ":" + strconv.Itoa(line)
+			},
+		},
+		{
+			name:   "function",
+			format: SourceFormatFunction,
+			expected: func(_ string, _ int, function string) string {
+				return function
+			},
+		},
+		{
+			name:   "module",
+			format: SourceFormatModule,
+			expected: func(_ string, line int, function string) string {
+				return "source_test.go:" + strconv.Itoa(line) + " " + function
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			buf := &bytes.Buffer{}
+			l := NewLogger(WithFormat(FormatJSON), WithWriter(buf), WithSource(""), WithSourceFormat(tt.format))
+
+			pc, file, line, _ := runtime.Caller(0)
+			l.Info(NoContext, "message")
+
+			entries := decodeEntries(t, buf)
+			require.Len(t, entries, 1)
+			assert.Equal(t, tt.expected(file, line+1, runtime.FuncForPC(pc).Name()), entries[0][SourceKey])
+		})
+	}
+}
+
+func Test_WithSourceFormat_Object(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	l := NewLogger(WithFormat(FormatJSON), WithWriter(buf), WithSource(""), WithSourceFormat(SourceFormatObject))
+
+	_, file, line, _ := runtime.Caller(0)
+	l.Info(NoContext, "message")
+
+	entries := decodeEntries(t, buf)
+	require.Len(t, entries, 1)
+	assert.Equal(t, map[string]any{
+		SourceFileKey:     file,
+		SourceLineKey:     float64(line + 1),
+		SourceFunctionKey: "github.com/anticrew/log.Test_WithSourceFormat_Object",
+	}, entries[0][SourceKey])
+}
+
+func Test_SourceFormat_IsValid(t *testing.T) {
+	t.Parallel()
+
+	for f := SourceFormatShort; f <= SourceFormatObject; f++ {
+		assert.True(t, f.IsValid(), f.String())
+	}
+
+	assert.False(t, SourceFormat(100).IsValid())
+	assert.Equal(t, "SourceFormat<100>", SourceFormat(100).String())
+}
+
+// shortPath
+// Сокращает путь к файлу до названия директории и файла, аналогично SourceFormatShort
+func shortPath(file string) string {
+	return filepath.Base(filepath.Dir(file)) + "/" + filepath.Base(file)
+}
@@ -0,0 +1,52 @@
+package log
+
+// Stats
+// Получатель событий записи логов, например, для подсчета метрик (см. пакет metrics). Методы вызываются
+// синхронно при записи каждого лога и должны быть безопасны для конкурентного использования
+type Stats interface {
+	// Written
+	// Лог с указанным уровнем записан Logger с указанным полным именем
+	Written(level Level, name string)
+
+	// Dropped
+	// Запись лога отменена Hook
+	Dropped(level Level, name string)
+
+	// Failed
+	// Лог не записан из-за ошибки энкодера или потока вывода
+	Failed(level Level, name string, err error)
+}
+
+// WithStats
+// Устанавливает получателя событий записи логов
+func WithStats(stats Stats) Option {
+	return func(o Options) Options {
+		o.Stats = stats
+		return o
+	}
+}
+
+// dropped
+// Сообщает об отмене записи лога получателю Options.Stats
+func (o *Options) dropped(level Level, name string) {
+	if o.Stats != nil {
+		o.Stats.Dropped(level, name)
+	}
+}
+
+// report
+// Сообщает о результате записи лога получателю Options.Stats и передает ошибку в Options.ErrorHandler
+func (o *Options) report(level Level, name string, err error) {
+	o.handleError(err)
+
+	if o.Stats == nil {
+		return
+	}
+
+	if err != nil {
+		o.Stats.Failed(level, name, err)
+		return
+	}
+
+	o.Stats.Written(level, name)
+}
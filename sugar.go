@@ -0,0 +1,320 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// BadKey
+// Ключ для значений без ключа или с ключом, не являющимся строкой, в наборе ключей и значений SugaredLogger
+const BadKey = "!BADKEY"
+
+// _sugarSkip
+// Кол-во вызовов SugaredLogger между местом вызова и методом Logger: метод уровня, logf или logw и write
+const _sugarSkip = 3
+
+// Tracef
+// Записывает TRACE-лог с сообщением, отформатированным с помощью fmt.Sprintf
+func Tracef(ctx context.Context, format string, args ...any) {
+	defaultSugar(ctx).Tracef(ctx, format, args...)
+}
+
+// Debugf
+// Записывает DEBUG-лог с сообщением, отформатированным с помощью fmt.Sprintf
+func Debugf(ctx context.Context, format string, args ...any) {
+	defaultSugar(ctx).Debugf(ctx, format, args...)
+}
+
+// Infof
+// Записывает INFO-лог с сообщением, отформатированным с помощью fmt.Sprintf
+func Infof(ctx context.Context, format string, args ...any) {
+	defaultSugar(ctx).Infof(ctx, format, args...)
+}
+
+// Warnf
+// Записывает WARN-лог с ошибкой и сообщением, отформатированным с помощью fmt.Sprintf
+func Warnf(ctx context.Context, err error, format string, args ...any) {
+	defaultSugar(ctx).Warnf(ctx, err, format, args...)
+}
+
+// Errorf
+// Записывает ERROR-лог с ошибкой и сообщением, отформатированным с помощью fmt.Sprintf
+func Errorf(ctx context.Context, err error, format string, args ...any) {
+	defaultSugar(ctx).Errorf(ctx, err, format, args...)
+}
+
+// Tracew
+// Записывает TRACE-лог с сообщением и аргументами, указанными парами ключ-значение
+func Tracew(ctx context.Context, msg string, keysAndValues ...any) {
+	defaultSugar(ctx).Tracew(ctx, msg, keysAndValues...)
+}
+
+// Debugw
+// Записывает DEBUG-лог с сообщением и аргументами, указанными парами ключ-значение
+func Debugw(ctx context.Context, msg string, keysAndValues ...any) {
+	defaultSugar(ctx).Debugw(ctx, msg, keysAndValues...)
+}
+
+// Infow
+// Записывает INFO-лог с сообщением и аргументами, указанными парами ключ-значение
+func Infow(ctx context.Context, msg string, keysAndValues ...any) {
+	defaultSugar(ctx).Infow(ctx, msg, keysAndValues...)
+}
+
+// Warnw
+// Записывает WARN-лог с ошибкой, сообщением и аргументами, указанными парами ключ-значение
+func Warnw(ctx context.Context, err error, msg string, keysAndValues ...any) {
+	defaultSugar(ctx).Warnw(ctx, err, msg, keysAndValues...)
+}
+
+// Errorw
+// Записывает ERROR-лог с ошибкой, сообщением и аргументами, указанными парами ключ-значение
+func Errorw(ctx context.Context, err error, msg string, keysAndValues ...any) {
+	defaultSugar(ctx).Errorw(ctx, err, msg, keysAndValues...)
+}
+
+// defaultSugar
+// Возвращает SugaredLogger на основе Logger из context.Context или Logger по умолчанию, пропускающий при определении
+// источника вызов функции пакета
+func defaultSugar(ctx context.Context) SugaredLogger {
+	return NewSugaredLogger(AddCallerSkip(defaultLoggerFor(ctx), 1))
+}
+
+// SugaredLogger
+// Обертка над Logger для быстрой отладки и переноса кода с logrus и zap.SugaredLogger: сообщения в стиле fmt.Printf
+// (методы с суффиксом f) и аргументы в виде пар ключ-значение (методы с суффиксом w). Работает медленнее Logger,
+// так как значения преобразуются в Arg при каждой записи. Форматирование и преобразование выполняются только для
+// включенных уровней
+type SugaredLogger struct {
+	l Logger
+}
+
+// NewSugaredLogger
+// Создает SugaredLogger на основе Logger. Если Logger реализует CallerSkipper, как Logger, созданный NewLogger,
+// источник лога указывает на место вызова метода SugaredLogger, для остальных реализаций дополнительные вызовы можно
+// пропустить с помощью WithSkip
+func NewSugaredLogger(l Logger) SugaredLogger {
+	return SugaredLogger{
+		l: AddCallerSkip(l, _sugarSkip),
+	}
+}
+
+// Desugar
+// Возвращает Logger, на основе которого создан SugaredLogger
+func (s SugaredLogger) Desugar() Logger {
+	return AddCallerSkip(s.l, -_sugarSkip)
+}
+
+// With
+// Создает SugaredLogger с аргументами, указанными парами ключ-значение, аналогично Logger.WithArgs
+func (s SugaredLogger) With(keysAndValues ...any) SugaredLogger {
+	return SugaredLogger{
+		l: s.l.WithArgs(sugarArgs(keysAndValues)...),
+	}
+}
+
+// Named
+// Создает SugaredLogger с именем, добавленным к имени текущего Logger, аналогично Logger.Named
+func (s SugaredLogger) Named(name string) SugaredLogger {
+	return SugaredLogger{
+		l: s.l.Named(name),
+	}
+}
+
+// Enabled
+// Сообщает, будет ли записан лог с указанным уровнем
+func (s SugaredLogger) Enabled(ctx context.Context, level Level) bool {
+	return s.l.Enabled(ctx, level)
+}
+
+// Tracef
+// Записывает TRACE-лог с сообщением, отформатированным с помощью fmt.Sprintf
+func (s SugaredLogger) Tracef(ctx context.Context, format string, args ...any) {
+	s.logf(ctx, LevelTrace, nil, format, args)
+}
+
+// Debugf
+// Записывает DEBUG-лог с сообщением, отформатированным с помощью fmt.Sprintf
+func (s SugaredLogger) Debugf(ctx context.Context, format string, args ...any) {
+	s.logf(ctx, LevelDebug, nil, format, args)
+}
+
+// Infof
+// Записывает INFO-лог с сообщением, отформатированным с помощью fmt.Sprintf
+func (s SugaredLogger) Infof(ctx context.Context, format string, args ...any) {
+	s.logf(ctx, LevelInfo, nil, format, args)
+}
+
+// Warnf
+// Записывает WARN-лог с ошибкой и сообщением, отформатированным с помощью fmt.Sprintf
+func (s SugaredLogger) Warnf(ctx context.Context, err error, format string, args ...any) {
+	s.logf(ctx, LevelWarn, err, format, args)
+}
+
+// Errorf
+// Записывает ERROR-лог с ошибкой и сообщением, отформатированным с помощью fmt.Sprintf
+func (s SugaredLogger) Errorf(ctx context.Context, err error, format string, args ...any) {
+	s.logf(ctx, LevelError, err, format, args)
+}
+
+// Writef
+// Записывает лог с указанным уровнем и сообщением, отформатированным с помощью fmt.Sprintf
+func (s SugaredLogger) Writef(ctx context.Context, level Level, format string, args ...any) {
+	s.logf(ctx, level, nil, format, args)
+}
+
+// Tracew
+// Записывает TRACE-лог с сообщением и аргументами, указанными парами ключ-значение
+func (s SugaredLogger) Tracew(ctx context.Context, msg string, keysAndValues ...any) {
+	s.logw(ctx, LevelTrace, nil, msg, keysAndValues)
+}
+
+// Debugw
+// Записывает DEBUG-лог с сообщением и аргументами, указанными парами ключ-значение
+func (s SugaredLogger) Debugw(ctx context.Context, msg string, keysAndValues ...any) {
+	s.logw(ctx, LevelDebug, nil, msg, keysAndValues)
+}
+
+// Infow
+// Записывает INFO-лог с сообщением и аргументами, указанными парами ключ-значение
+func (s SugaredLogger) Infow(ctx context.Context, msg string, keysAndValues ...any) {
+	s.logw(ctx, LevelInfo, nil, msg, keysAndValues)
+}
+
+// Warnw
+// Записывает WARN-лог с ошибкой, сообщением и аргументами, указанными парами ключ-значение
+func (s SugaredLogger) Warnw(ctx context.Context, err error, msg string, keysAndValues ...any) {
+	s.logw(ctx, LevelWarn, err, msg, keysAndValues)
+}
+
+// Errorw
+// Записывает ERROR-лог с ошибкой, сообщением и аргументами, указанными парами ключ-значение
+func (s SugaredLogger) Errorw(ctx context.Context, err error, msg string, keysAndValues ...any) {
+	s.logw(ctx, LevelError, err, msg, keysAndValues)
+}
+
+// Writew
+// Записывает лог с указанным уровнем, сообщением и аргументами, указанными парами ключ-значение
+func (s SugaredLogger) Writew(ctx context.Context, level Level, msg string, keysAndValues ...any) {
+	s.logw(ctx, level, nil, msg, keysAndValues)
+}
+
+func (s SugaredLogger) logf(ctx context.Context, level Level, err error, format string, args []any) {
+	if !s.l.Enabled(ctx, level) {
+		return
+	}
+
+	s.write(ctx, level, err, fmt.Sprintf(format, args...), nil)
+}
+
+func (s SugaredLogger) logw(ctx context.Context, level Level, err error, msg string, keysAndValues []any) {
+	if !s.l.Enabled(ctx, level) {
+		return
+	}
+
+	s.write(ctx, level, err, msg, sugarArgs(keysAndValues))
+}
+
+// write
+// Записывает лог методом Logger, соответствующим уровню, чтобы глубина вызовов не зависела от уровня
+func (s SugaredLogger) write(ctx context.Context, level Level, err error, msg string, args []Arg) {
+	switch {
+	case err != nil && level == LevelWarn:
+		s.l.Warn(ctx, err, msg, args...)
+	case err != nil:
+		s.l.Error(ctx, err, msg, args...)
+	default:
+		s.l.Write(ctx, level, msg, args...)
+	}
+}
+
+// sugarArgs
+// Преобразует пары ключ-значение в Arg. Значения типа Arg добавляются без изменений, значения без ключа или с
+// ключом, не являющимся строкой, добавляются с ключом BadKey
+func sugarArgs(keysAndValues []any) []Arg {
+	if len(keysAndValues) == 0 {
+		return nil
+	}
+
+	args := make([]Arg, 0, (len(keysAndValues)+1)/2)
+
+	for i := 0; i < len(keysAndValues); i++ {
+		switch key := keysAndValues[i].(type) {
+		case Arg:
+			args = append(args, key)
+
+		case string:
+			if i == len(keysAndValues)-1 {
+				args = append(args, String(BadKey, key))
+				continue
+			}
+
+			i++
+			args = append(args, sugarArg(key, keysAndValues[i]))
+
+		default:
+			args = append(args, sugarArg(BadKey, key))
+		}
+	}
+
+	return args
+}
+
+// sugarArg
+// Создает типизированный Arg для значения с помощью соответствующего конструктора
+func sugarArg(key string, value any) Arg {
+	switch v := value.(type) {
+	case string:
+		return String(key, v)
+	case bool:
+		return Bool(key, v)
+	case int:
+		return Int(key, v)
+	case int8:
+		return Int64(key, int64(v))
+	case int16:
+		return Int64(key, int64(v))
+	case int32:
+		return Int64(key, int64(v))
+	case int64:
+		return Int64(key, v)
+	case uint:
+		return Uint(key, v)
+	case uint8:
+		return Uint64(key, uint64(v))
+	case uint16:
+		return Uint64(key, uint64(v))
+	case uint32:
+		return Uint64(key, uint64(v))
+	case uint64:
+		return Uint64(key, v)
+	case float32:
+		return Float32(key, v)
+	case float64:
+		return Float64(key, v)
+	case time.Time:
+		return Time(key, v)
+	case time.Duration:
+		return Duration(key, v)
+	case error:
+		return String(key, errorString(v))
+	default:
+		return Any(key, v)
+	}
+}
+
+// errorString
+// Возвращает текст ошибки. Для типизированного nil возвращает "nil", как Err, вместо вызова Error у nil-значения
+func errorString(err error) string {
+	switch v := reflect.ValueOf(err); v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		if v.IsNil() {
+			return "nil"
+		}
+	default:
+	}
+
+	return err.Error()
+}
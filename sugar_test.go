@@ -0,0 +1,191 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sugarError
+// Ошибка с методом Error у указателя
+type sugarError struct {
+	msg string
+}
+
+func (e *sugarError) Error() string {
+	return e.msg
+}
+
+func Test_sugarArgs(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	tests := []struct {
+		name string
+		kv   []any
+		want []Arg
+	}{
+		{
+			name: "empty",
+			kv:   nil,
+			want: nil,
+		},
+		{
+			name: "typed",
+			kv: []any{
+				"s", "v", "b", true, "i", 1, "i32", int32(2), "u", uint(3), "u8", uint8(4),
+				"f", 1.5, "t", now, "d", time.Second, "err", errors.New("failure"),
+			},
+			want: []Arg{
+				String("s", "v"), Bool("b", true), Int("i", 1), Int64("i32", 2), Uint("u", 3), Uint64("u8", 4),
+				Float64("f", 1.5), Time("t", now), Duration("d", time.Second), String("err", "failure"),
+			},
+		},
+		{
+			name: "typed nil error",
+			kv:   []any{"err", (*sugarError)(nil)},
+			want: []Arg{String("err", "nil")},
+		},
+		{
+			name: "arg",
+			kv:   []any{Int("n", 1), "s", "v"},
+			want: []Arg{Int("n", 1), String("s", "v")},
+		},
+		{
+			name: "missing value",
+			kv:   []any{"s", "v", "orphan"},
+			want: []Arg{String("s", "v"), String(BadKey, "orphan")},
+		},
+		{
+			name: "non-string key",
+			kv:   []any{42, "s", "v"},
+			want: []Arg{Int(BadKey, 42), String("s", "v")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, sugarArgs(tt.kv))
+		})
+	}
+}
+
+func Test_SugaredLogger(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	s := NewLogger(WithFormat(FormatJSON), WithWriter(buf), WithLevel("", LevelInfo)).Sugar()
+
+	s.Debugf(NoContext, "skipped %d", 1)
+	s.Infof(NoContext, "hello %s", "world")
+	s.With("component", "api").Infow(NoContext, "request", "status", 200, "orphan")
+	s.Errorw(NoContext, assert.AnError, "failure", "attempt", 2)
+	s.Warnf(NoContext, nil, "retry in %s", time.Second)
+
+	entries := decodeEntries(t, buf)
+	require.Len(t, entries, 4)
+
+	assert.Equal(t, "hello world", entries[0][MessageKey])
+
+	assert.Equal(t, "request", entries[1][MessageKey])
+	assert.Equal(t, "api", entries[1]["component"])
+	assert.InDelta(t, 200, entries[1]["status"], 0)
+	assert.Equal(t, "orphan", entries[1][BadKey])
+
+	assert.Equal(t, "ERROR", entries[2][LevelKey])
+	assert.Equal(t, assert.AnError.Error(), entries[2][ErrorKey])
+	assert.InDelta(t, 2, entries[2]["attempt"], 0)
+
+	assert.Equal(t, "WARN", entries[3][LevelKey])
+	assert.Equal(t, "retry in 1s", entries[3][MessageKey])
+}
+
+func Test_SugaredLogger_TypedNilError(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	s := NewLogger(WithFormat(FormatJSON), WithWriter(buf)).Sugar()
+
+	require.NotPanics(t, func() {
+		s.Infow(NoContext, "typed nil", "err", (*sugarError)(nil))
+	})
+
+	entries := decodeEntries(t, buf)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "nil", entries[0]["err"])
+}
+
+func Test_SugaredLogger_Source(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	l := NewLogger(WithFormat(FormatJSON), WithWriter(buf), WithSource(""))
+
+	l.Sugar().Infof(NoContext, "sugared")
+	l.Sugar().With("k", "v").Errorw(NoContext, assert.AnError, "sugared")
+	l.Sugar().Desugar().Info(NoContext, "desugared")
+
+	entries := decodeEntries(t, buf)
+	require.Len(t, entries, 3)
+
+	for _, entry := range entries {
+		assert.Contains(t, fmt.Sprint(entry[SourceKey]), "sugar_test.go")
+	}
+}
+
+func Test_Sugared_Default_Source(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	ctx := SetContextLogger(context.Background(), NewLogger(WithFormat(FormatJSON), WithWriter(buf), WithSource(""),
+		WithLevel("", LevelTrace)))
+
+	Tracef(ctx, "tracef")
+	Debugf(ctx, "debugf")
+	Infof(ctx, "infof")
+	Warnf(ctx, assert.AnError, "warnf")
+	Errorf(ctx, assert.AnError, "errorf")
+	Tracew(ctx, "tracew")
+	Debugw(ctx, "debugw")
+	Infow(ctx, "infow")
+	Warnw(ctx, assert.AnError, "warnw")
+	Errorw(ctx, assert.AnError, "errorw")
+
+	entries := decodeEntries(t, buf)
+	require.Len(t, entries, 10)
+
+	for _, entry := range entries {
+		assert.Contains(t, fmt.Sprint(entry[SourceKey]), "sugar_test.go", entry[MessageKey])
+	}
+}
+
+//nolint:paralleltest // replaces default logger
+func Test_Sugared_Default(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	prev := GetDefault()
+	SetDefault(NewLogger(WithFormat(FormatJSON), WithWriter(buf)))
+	t.Cleanup(func() {
+		SetDefault(prev)
+	})
+
+	ctx := AddContextArgs(context.Background(), String("request_id", "r-1"))
+	Infof(ctx, "n=%d", 1)
+	Infow(ctx, "kv", "n", 2)
+
+	entries := decodeEntries(t, buf)
+	require.Len(t, entries, 2)
+
+	assert.Equal(t, "n=1", entries[0][MessageKey])
+	assert.Equal(t, "r-1", entries[0]["request_id"])
+	assert.InDelta(t, 2, entries[1]["n"], 0)
+}
@@ -0,0 +1,333 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anticrew/go-x/xio"
+)
+
+// Facility
+// Источник сообщений syslog (RFC 5424, раздел 6.2.1)
+type Facility int
+
+// Источники сообщений, описание см. в RFC 5424
+const (
+	FacilityKern Facility = iota
+	FacilityUser
+	FacilityMail
+	FacilityDaemon
+	FacilityAuth
+	FacilitySyslog
+	FacilityLPR
+	FacilityNews
+	FacilityUUCP
+	FacilityCron
+	FacilityAuthPriv
+	FacilityFTP
+	FacilityNTP
+	FacilityAudit
+	FacilityAlert
+	FacilityClock
+	FacilityLocal0
+	FacilityLocal1
+	FacilityLocal2
+	FacilityLocal3
+	FacilityLocal4
+	FacilityLocal5
+	FacilityLocal6
+	FacilityLocal7
+)
+
+// IsValid
+// Сообщает, что Facility входит в диапазон, определенный RFC 5424
+func (f Facility) IsValid() bool {
+	return f >= FacilityKern && f <= FacilityLocal7
+}
+
+// Severity
+// Важность сообщения syslog (RFC 5424, раздел 6.2.1)
+type Severity int
+
+// Уровни важности сообщений, описание см. в RFC 5424
+const (
+	SeverityEmergency Severity = iota
+	SeverityAlert
+	SeverityCritical
+	SeverityError
+	SeverityWarning
+	SeverityNotice
+	SeverityInfo
+	SeverityDebug
+)
+
+// LevelSeverity
+// Возвращает важность syslog для уровня: LevelError и выше - SeverityError, уровни выше LevelError на 4 и более -
+// SeverityCritical, LevelWarn - SeverityWarning, уровни между LevelInfo и LevelWarn - SeverityNotice, LevelInfo -
+// SeverityInfo, уровни ниже LevelInfo - SeverityDebug
+func LevelSeverity(level Level) Severity {
+	switch {
+	case level >= LevelError+4:
+		return SeverityCritical
+	case level >= LevelError:
+		return SeverityError
+	case level >= LevelWarn:
+		return SeverityWarning
+	case level > LevelInfo:
+		return SeverityNotice
+	case level == LevelInfo:
+		return SeverityInfo
+	default:
+		return SeverityDebug
+	}
+}
+
+// SyslogStructuredDataID
+// Идентификатор элемента структурированных данных RFC 5424 по умолчанию, номер 32473 зарезервирован для примеров
+// (RFC 5612)
+const SyslogStructuredDataID = "log@32473"
+
+// WithSyslog
+// Определяет источник и название приложения для форматов FormatSyslog5424 и FormatSyslog3164. Если название не
+// указано, используется название исполняемого файла
+func WithSyslog(facility Facility, appName string) Option {
+	if !facility.IsValid() {
+		return emptyOption
+	}
+
+	return func(o Options) Options {
+		o.SyslogFacility = facility
+		o.SyslogAppName = appName
+		return o
+	}
+}
+
+// WithSyslogStructuredDataID
+// Определяет идентификатор элемента структурированных данных RFC 5424, в который записываются аргументы лога,
+// например, "app@<номер предприятия IANA>"
+func WithSyslogStructuredDataID(id string) Option {
+	if len(id) == 0 {
+		return emptyOption
+	}
+
+	return func(o Options) Options {
+		o.SyslogStructuredDataID = id
+		return o
+	}
+}
+
+// syslogEncoder
+// Общий для драйверов энкодер форматов FormatSyslog5424 и FormatSyslog3164. Заголовок вычисляется при создании
+// Logger
+type syslogEncoder struct {
+	format   Format
+	facility Facility
+	hostname string
+	appName  string
+	procID   string
+	sdID     string
+}
+
+func newSyslogEncoder(opt Options) *syslogEncoder {
+	hostname, err := os.Hostname()
+	if err != nil || len(hostname) == 0 {
+		hostname = "-"
+	}
+
+	appName := opt.SyslogAppName
+	if len(appName) == 0 {
+		appName = filepath.Base(os.Args[0])
+	}
+
+	sdID := opt.SyslogStructuredDataID
+	if len(sdID) == 0 {
+		sdID = SyslogStructuredDataID
+	}
+
+	return &syslogEncoder{
+		format:   opt.Format,
+		facility: opt.SyslogFacility,
+		hostname: syslogHeaderField(hostname, 255),
+		appName:  syslogHeaderField(appName, 48),
+		procID:   strconv.Itoa(os.Getpid()),
+		sdID:     sdID,
+	}
+}
+
+// encode
+// Записывает лог в buf в формате syslog с переводом строки в конце
+func (e *syslogEncoder) encode(buf *xio.Buffer, level Level, t time.Time, msg string, params []param) {
+	buf.WriteByte('<')
+	buf.WriteInt64(int64(e.facility)*8 + int64(LevelSeverity(level)))
+	buf.WriteByte('>')
+
+	if e.format == FormatSyslog3164 {
+		e.encode3164(buf, t, msg, params)
+	} else {
+		e.encode5424(buf, t, msg, params)
+	}
+
+	buf.WriteByte('\n')
+}
+
+// encode5424
+// Записывает лог в формате RFC 5424, аргументы записываются в элемент структурированных данных
+func (e *syslogEncoder) encode5424(buf *xio.Buffer, t time.Time, msg string, params []param) {
+	buf.WriteString("1 ")
+	buf.WriteTime(t, "2006-01-02T15:04:05.000000Z07:00")
+	buf.WriteByte(' ').WriteString(e.hostname)
+	buf.WriteByte(' ').WriteString(e.appName)
+	buf.WriteByte(' ').WriteString(e.procID)
+	buf.WriteString(" - ") // MSGID
+
+	if len(params) == 0 {
+		buf.WriteByte('-')
+	} else {
+		buf.WriteByte('[').WriteString(e.sdID)
+
+		for _, p := range params {
+			buf.WriteByte(' ').WriteString(syslogParamName(p.key))
+			buf.WriteString(`="`)
+			writeSyslogParamValue(buf, syslogValue(p.value))
+			buf.WriteByte('"')
+		}
+
+		buf.WriteByte(']')
+	}
+
+	if len(msg) > 0 {
+		buf.WriteByte(' ').WriteString(syslogMessage(msg))
+	}
+}
+
+// encode3164
+// Записывает лог в формате RFC 3164, аргументы добавляются к сообщению в виде key=value
+func (e *syslogEncoder) encode3164(buf *xio.Buffer, t time.Time, msg string, params []param) {
+	buf.WriteTime(t, time.Stamp)
+	buf.WriteByte(' ').WriteString(e.hostname)
+	buf.WriteByte(' ').WriteString(e.appName)
+	buf.WriteByte('[').WriteString(e.procID).WriteString("]: ")
+	buf.WriteString(syslogMessage(msg))
+
+	for i, p := range params {
+		if i > 0 || len(msg) > 0 {
+			buf.WriteByte(' ')
+		}
+
+		buf.WriteString(syslogParamName(p.key)).WriteByte('=')
+
+		v := syslogValue(p.value)
+		if len(v) == 0 || strings.ContainsAny(v, " \"=\\") || strings.IndexFunc(v, isNotPrintable) >= 0 {
+			v = strconv.Quote(v)
+		}
+
+		buf.WriteString(v)
+	}
+}
+
+// syslogValue
+// Возвращает строковое представление значения аргумента: составные значения записываются в JSON
+func syslogValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case time.Duration:
+		return v.String()
+	case error:
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	}
+
+	switch reflect.ValueOf(v).Kind() { //nolint:exhaustive // other kinds are formatted with fmt
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct, reflect.Pointer:
+		if b, err := json.Marshal(v); err == nil {
+			return string(b)
+		}
+	}
+
+	return fmt.Sprint(v)
+}
+
+// syslogParamName
+// Возвращает допустимое название параметра структурированных данных (SD-NAME): до 32 печатных символов ASCII без
+// '=', ' ', ']' и '"'
+func syslogParamName(key string) string {
+	if len(key) == 0 {
+		return "_"
+	}
+
+	if len(key) > 32 {
+		key = key[:32]
+	}
+
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' || r == '=' || r == ']' || r == '"' {
+			return '_'
+		}
+
+		return r
+	}, key)
+}
+
+// writeSyslogParamValue
+// Записывает значение параметра структурированных данных, экранируя '"', '\' и ']', переводы строк заменяются на
+// \n и \r, чтобы лог занимал одну строку
+func writeSyslogParamValue(buf *xio.Buffer, v string) {
+	for i := range len(v) {
+		switch c := v[i]; c {
+		case '"', '\\', ']':
+			buf.WriteByte('\\').WriteByte(c)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		default:
+			buf.WriteByte(c)
+		}
+	}
+}
+
+// syslogHeaderField
+// Возвращает допустимое значение поля заголовка: до max печатных символов ASCII без пробелов или "-"
+func syslogHeaderField(s string, maxLen int) string {
+	s = strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' {
+			return '_'
+		}
+
+		return r
+	}, s)
+
+	if len(s) == 0 {
+		return "-"
+	}
+
+	return s[:min(len(s), maxLen)]
+}
+
+// syslogMessage
+// Заменяет переводы строк в сообщении, чтобы лог занимал одну строку
+func syslogMessage(msg string) string {
+	if !strings.ContainsAny(msg, "\r\n") {
+		return msg
+	}
+
+	return strings.NewReplacer("\r", `\r`, "\n", `\n`).Replace(msg)
+}
+
+func isNotPrintable(r rune) bool {
+	return !strconv.IsPrint(r)
+}
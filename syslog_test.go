@@ -0,0 +1,85 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FormatSyslog5424(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	l := NewLogger(WithFormat(FormatSyslog5424), WithWriter(buf), WithSyslog(FacilityLocal0, "my app"),
+		WithSyslogStructuredDataID("app@12345"))
+
+	l.WithArgs(String("user", "a b")).Named("svc").Warn(NoContext, errors.New(`bad "]`), "multi\nline",
+		Int("n", 1), Duration("took", time.Second), Any("m", map[string]int{"k": 1}),
+		String("time", "t"), String("bad key=", "v"),
+		LazyArgs(func() []Arg { return []Arg{Bool("lazy", true)} }),
+	)
+	l.Debug(NoContext, "")
+
+	hostname, err := os.Hostname()
+	require.NoError(t, err)
+
+	header := `1 \d{4}-\d\d-\d\dT\d\d:\d\d:\d\d\.\d{6}(Z|[+-]\d\d:\d\d) ` + regexp.QuoteMeta(hostname) + ` my_app ` +
+		strconv.Itoa(os.Getpid()) + ` - `
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 2)
+
+	assert.Regexp(t, `^<132>`+header+regexp.QuoteMeta(`[app@12345 user="a b" n="1" took="1s" m="{\"k\":1}" `+
+		`time="t" bad_key_="v" lazy="true" error="bad \"\]" logger="svc"] multi\nline`)+`$`, lines[0])
+	assert.Regexp(t, `^<135>`+header+`-$`, lines[1])
+}
+
+func Test_FormatSyslog3164(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	l := NewLogger(WithFormat(FormatSyslog3164), WithWriter(buf), WithSyslog(FacilityAuth, "app"))
+
+	l.Info(NoContext, "hello", String("user", "a b"), Int("n", 1), String("empty", ""))
+	l.Error(NoContext, nil, "", String("key", "value"))
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 2)
+
+	prefix := `[A-Z][a-z]{2} [ \d]\d \d\d:\d\d:\d\d \S+ app\[` + strconv.Itoa(os.Getpid()) + `\]: `
+
+	assert.Regexp(t, `^<38>`+prefix+regexp.QuoteMeta(`hello user="a b" n=1 empty=""`)+`$`, lines[0])
+	assert.Regexp(t, `^<35>`+prefix+`key=value$`, lines[1])
+}
+
+func Test_LevelSeverity(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, SeverityDebug, LevelSeverity(LevelTrace))
+	assert.Equal(t, SeverityDebug, LevelSeverity(LevelDebug))
+	assert.Equal(t, SeverityInfo, LevelSeverity(LevelInfo))
+	assert.Equal(t, SeverityNotice, LevelSeverity(LevelInfo+2))
+	assert.Equal(t, SeverityWarning, LevelSeverity(LevelWarn))
+	assert.Equal(t, SeverityError, LevelSeverity(LevelError))
+	assert.Equal(t, SeverityCritical, LevelSeverity(LevelError+4))
+}
+
+func Test_ParseFormat_Syslog(t *testing.T) {
+	t.Parallel()
+
+	f, err := ParseFormat("SYSLOG5424")
+	require.NoError(t, err)
+	assert.Equal(t, FormatSyslog5424, f)
+
+	f, err = ParseFormat("syslog3164")
+	require.NoError(t, err)
+	assert.Equal(t, FormatSyslog3164, f)
+}
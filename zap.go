@@ -4,19 +4,21 @@ package log
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"math"
+	"slices"
 	"time"
 
 	zaplogfmt "github.com/sykesm/zap-logfmt"
 
+	"github.com/anticrew/log/internal/caller"
+
 	"github.com/anticrew/go-x/pool"
-	"github.com/anticrew/go-x/xio"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
-type Level = zapcore.Level
-
 type Arg = zap.Field
 
 func Err(err error) Arg {
@@ -72,45 +74,108 @@ func Any(key string, value any) Arg {
 	return zap.Any(key, value)
 }
 
-const (
-	LevelTrace = zap.DebugLevel - 1
-	LevelDebug = zap.DebugLevel
-	LevelInfo  = zap.InfoLevel
-	LevelWarn  = zap.WarnLevel
-	LevelError = zap.ErrorLevel
-)
+// ArgKey
+// Возвращает ключ аргумента
+func ArgKey(a Arg) string {
+	return a.Key
+}
+
+// ArgValue
+// Возвращает значение аргумента, вычисляя ленивые значения. Для аргументов, созданных LazyArgs, возвращает []Arg
+func ArgValue(a Arg) any {
+	if fn, ok := a.Interface.(lazyArgs); ok {
+		return fn()
+	}
+
+	enc := zapcore.NewMapObjectEncoder()
+	a.AddTo(enc)
+
+	return enc.Fields[a.Key]
+}
+
+// Lazy
+// Создает аргумент, значение которого вычисляется с помощью fn только при записи лога
+func Lazy(key string, fn func() any) Arg {
+	f := zap.Inline(lazyValue{key: key, fn: fn})
+	f.Key = key
+
+	return f
+}
+
+// LazyArgs
+// Создает аргумент, раскрывающийся в набор аргументов, вычисляемый с помощью fn только при записи лога
+func LazyArgs(fn func() []Arg) Arg {
+	return zap.Inline(lazyArgs(fn))
+}
+
+// lazyValue
+// Значение аргумента, вычисляемое энкодером zap только при записи лога
+type lazyValue struct {
+	key string
+	fn  func() any
+}
+
+func (v lazyValue) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	zap.Any(v.key, v.fn()).AddTo(enc)
+	return nil
+}
+
+// lazyArgs
+// Набор аргументов, вычисляемый энкодером zap только при записи лога
+type lazyArgs func() []Arg
+
+func (f lazyArgs) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, arg := range f() {
+		arg.AddTo(enc)
+	}
+
+	return nil
+}
 
 type logger struct {
 	log *zap.Logger
 	opt Options
+
+	// args
+	// Аргументы Logger. При политике обработки повторяющихся ключей DuplicateKeysKeep добавляются в log с помощью
+	// zap.Logger.With, иначе обрабатываются при каждой записи лога. Используются для пересоздания в WithOptions
+	args []Arg
+
+	// reserved
+	// Зарезервированные ключи для обработки повторяющихся ключей
+	reserved []string
+
+	// bound
+	// Набор аргументов context.Context, добавленный с помощью WithContext
+	bound BoundContext
+
+	// callerSkip
+	// Кол-во вызовов для пропуска, добавленное оберткой над Logger, например, ContextLogger
+	callerSkip int
+
+	// name
+	// Полное имя Logger, заданное с помощью Named
+	name string
+
+	// capture
+	// Поток вывода, сохраняющий записанные байты для WriteHook, nil без WriteHook
+	capture *captureWriter
 }
 
 func NewLogger(options ...Option) Logger {
 	return createFromOptions(defaultOptions(), options)
 }
 
-func createFromOptions(opt Options, options []Option) Logger {
-	opt = optionChain(options).apply(opt)
+func createFromOptions(opt Options, options []Option) *logger {
+	opt = formatOptions(optionChain(options).apply(opt))
 
 	cfg := zap.NewProductionEncoderConfig()
 	cfg.LevelKey = opt.LevelKey
 
-	levels := newLevelsConfig()
+	levels := newLevelsConfig(opt.LevelNames)
 	cfg.EncodeLevel = levels.encode
 
-	cfg.CallerKey = opt.SourceKey
-	if len(opt.SourceKey) > 0 {
-		cfg.EncodeCaller = func(caller zapcore.EntryCaller, encoder zapcore.PrimitiveArrayEncoder) {
-			buf := xio.NewBuffer()
-			defer buf.Dispose()
-
-			buf.WriteString(caller.TrimmedPath()).
-				WriteByte(' ').
-				WriteString(caller.Function)
-
-			encoder.AppendByteString(buf.Bytes())
-		}
-	}
+	cfg.CallerKey = zapcore.OmitKey // source is captured manually to share the format with slog
 
 	if len(opt.TimeKey) > 0 {
 		cfg.TimeKey = opt.TimeKey
@@ -125,53 +190,137 @@ func createFromOptions(opt Options, options []Option) Logger {
 		cfg.MessageKey = opt.MessageKey
 	}
 
+	cfg.NameKey = opt.NameKey
+
 	var encoder zapcore.Encoder
-	switch opt.Format {
-	case FormatText:
-		encoder = zapcore.NewConsoleEncoder(cfg)
-	case FormatJSON:
+	switch {
+	case opt.encodesParams():
+		encoder = newZapParamsEncoder(opt)
+	case opt.Format == FormatJSON:
 		encoder = zapcore.NewJSONEncoder(cfg)
-	case FormatLogFmt:
+	case opt.Format == FormatLogFmt:
 		encoder = zaplogfmt.NewEncoder(cfg)
+	default:
+		encoder = zapcore.NewConsoleEncoder(cfg)
 	}
 
-	writer, ok := opt.Writer.(zapcore.WriteSyncer)
-	if !ok {
-		writer = &zapWriter{
-			out: opt.Writer,
-		}
+	out := opt.output()
+
+	capture := newCaptureWriter(opt, out)
+	if capture != nil {
+		out = capture
 	}
 
-	l := zap.New(
-		zapcore.NewCore(
-			encoder,
-			&zapWriter{
-				out: writer,
-			},
-			opt.Level,
-		),
-		zap.WithCaller(opt.AddSource),
-		zap.AddCallerSkip(opt.Skip+2), // +2 to skip logAttrs and level-dependent function
+	// zapWriter hides Sync of the output: LevelWarn and LevelError are above zapcore.ErrorLevel, so zap would sync
+	// the output after each of them. The output is synced once by Logger.Close
+	var core zapcore.Core = zapcore.NewCore(
+		encoder,
+		&zapWriter{
+			out: out,
+		},
+		toZapLevel(opt.Level),
 	)
 
+	if opt.Stats != nil || opt.ErrorHandler != nil {
+		core = &reportCore{
+			Core: core,
+			opt:  &opt,
+		}
+	}
+
+	zapOpts := []zap.Option{
+		zap.WithPanicHook(noopHook{}), // levels are not terminal, LevelWarn matches zapcore.PanicLevel
+		zap.WithFatalHook(noopHook{}),
+		zap.AddStacktrace(zap.LevelEnablerFunc(func(zapcore.Level) bool { return false })),
+	}
+
+	if opt.ErrorHandler != nil {
+		// write errors are passed to ErrorHandler by reportCore
+		zapOpts = append(zapOpts, zap.ErrorOutput(zapcore.AddSync(io.Discard)))
+	}
+
+	l := zap.New(core, zapOpts...)
+
 	return &logger{
-		log: l,
-		opt: opt,
+		log:      l,
+		opt:      opt,
+		reserved: reservedKeys(opt, false),
+		capture:  capture,
 	}
 }
 
 func (l *logger) WithArgs(args ...Arg) Logger {
-	return &logger{
-		log: l.log.With(args...),
+	return l.withArgs(args)
+}
+
+func (l *logger) withArgs(args []Arg) *logger {
+	if len(args) == 0 {
+		return l
 	}
+
+	nl := *l
+	nl.args = append(slices.Clip(l.args), args...)
+
+	if !l.opt.mergeArgs() {
+		nl.log = l.log.With(args...)
+	}
+
+	return &nl
 }
 
 func (l *logger) WithContext(ctx context.Context) Logger {
-	return l.WithArgs(GetContextArgs(ctx)...)
+	bound, args, ok := l.bound.Bind(ctx)
+	if !ok {
+		return l
+	}
+
+	nl := *l.withArgs(args)
+	nl.bound = bound
+
+	return &nl
 }
 
 func (l *logger) WithOptions(options ...Option) Logger {
-	return createFromOptions(l.opt, options)
+	nl := *createFromOptions(l.opt, options).withArgs(l.args)
+	nl.bound = l.bound
+
+	if len(l.name) > 0 {
+		nl = *nl.withName(l.name)
+	}
+
+	nl.callerSkip = l.callerSkip
+
+	return &nl
+}
+
+func (l *logger) Named(name string) Logger {
+	if len(name) == 0 {
+		return l
+	}
+
+	return l.withName(name)
+}
+
+// withName
+// Добавляет имя к имени Logger с помощью zap.Logger.Named, который также разделяет имена точкой
+func (l *logger) withName(name string) *logger {
+	nl := *l
+	nl.log = l.log.Named(name)
+	nl.name = joinName(l.name, name)
+	nl.reserved = reservedKeys(l.opt, true)
+
+	return &nl
+}
+
+func (l *logger) WithCallerSkip(skip int) Logger {
+	nl := *l
+	nl.callerSkip += skip
+
+	return &nl
+}
+
+func (l *logger) Sugar() SugaredLogger {
+	return NewSugaredLogger(l)
 }
 
 func (l *logger) Trace(ctx context.Context, msg string, args ...Arg) {
@@ -202,42 +351,187 @@ var _argsPool = pool.NewPool[[]Arg](func() []Arg {
 	return make([]Arg, 0, 16)
 })
 
+func (l *logger) Enabled(_ context.Context, level Level) bool {
+	return l.log.Core().Enabled(toZapLevel(level))
+}
+
+func (l *logger) Close() error {
+	return l.opt.close()
+}
+
 func (l *logger) logAttrs(ctx context.Context, level Level, err error, msg string, args []Arg) {
+	if !l.Enabled(ctx, level) {
+		return
+	}
+
 	newArgs := _argsPool.Get()
 	defer _argsPool.Put(newArgs)
 
-	newArgs = append(newArgs, GetContextArgs(ctx)...)
+	if l.opt.mergeArgs() {
+		newArgs = append(newArgs, l.args...)
+	}
+
+	newArgs = l.bound.AppendArgs(newArgs, ctx)
 	newArgs = append(newArgs, args...)
 
 	if err != nil {
-		newArgs = append(newArgs, Err(err))
+		newArgs = append(newArgs, l.opt.errorArg(err))
+	}
+
+	newArgs = dedupeArgs(l.opt.DuplicateKeys, l.reserved, newArgs)
+
+	if l.opt.hooked() {
+		var frame *caller.Frame
+		if l.opt.AddSource {
+			frame, _ = caller.Lookup(l.opt.Skip + l.callerSkip + 2) // skip logAttrs and level-dependent function
+		}
+
+		l.logEntry(ctx, newEntry(l.opt, level, msg, l.name, newArgs, frame))
+		return
+	}
+
+	if l.opt.AddSource {
+		newArgs = append(newArgs, l.getSourceArg(2))
+	}
+
+	l.log.Log(toZapLevel(level), msg, newArgs...)
+}
+
+// logEntry
+// Вызывает Hook и записывает Entry напрямую в zapcore.Core, чтобы учесть изменения, внесенные Hook
+func (l *logger) logEntry(ctx context.Context, e *Entry) {
+	defer releaseEntry(e)
+
+	if !runHooks(ctx, l.opt.Hooks, e) {
+		l.opt.dropped(e.Level, e.Name)
+		return
+	}
+
+	ce := l.log.Core().Check(zapcore.Entry{
+		LoggerName: e.Name,
+		Time:       e.Time,
+		Level:      toZapLevel(e.Level),
+		Message:    e.Message,
+	}, nil)
+	if ce == nil {
+		return
+	}
+
+	fields := _argsPool.Get()
+	defer _argsPool.Put(fields)
+
+	fields = append(fields, e.Args...)
+
+	if f := e.sourceFrame(); f != nil && l.opt.AddSource {
+		fields = append(fields, l.sourceArg(f))
+	}
+
+	if l.capture == nil {
+		ce.Write(fields...)
+		return
+	}
+
+	buf := l.capture.begin()
+	ce.Write(fields...)
+	l.capture.end()
+
+	runWriteHooks(ctx, l.opt.WriteHooks, e, buf.Bytes())
+	buf.Dispose()
+}
+
+func (l *logger) getSourceArg(skip int) Arg {
+	c, err := caller.Lookup(l.opt.Skip + l.callerSkip + skip + 1)
+	if err != nil {
+		return zap.String(l.opt.SourceKey, fmt.Sprintf("(error = %v)", err))
+	}
+
+	return l.sourceArg(c)
+}
+
+func (l *logger) sourceArg(c *caller.Frame) Arg {
+	if l.opt.SourceFormat == SourceFormatObject {
+		return zap.Object(l.opt.SourceKey, (*sourceObject)(c))
+	}
+
+	return zap.String(l.opt.SourceKey, c.String(l.opt.SourceFormat.callerFormat()))
+}
+
+// sourceObject
+// Источник лога в формате SourceFormatObject. Приведение *caller.Frame к *sourceObject не выделяет память
+type sourceObject caller.Frame
+
+func (s *sourceObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString(SourceFileKey, s.File)
+	enc.AddInt(SourceLineKey, s.Line)
+	enc.AddString(SourceFunctionKey, s.Function)
+
+	return nil
+}
+
+func renameArg(a Arg, key string) Arg {
+	if v, ok := a.Interface.(lazyValue); ok {
+		return Lazy(key, v.fn)
 	}
 
-	l.log.Log(level, msg, newArgs...)
+	a.Key = key
+	return a
 }
 
 type levelsConfig struct {
-	values map[Level]string
+	names map[Level]string
 }
 
-func newLevelsConfig() *levelsConfig {
+func newLevelsConfig(names map[Level]string) *levelsConfig {
 	return &levelsConfig{
-		values: map[Level]string{
-			LevelTrace: _traceValue,
-			LevelDebug: _debugValue,
-			LevelInfo:  _infoValue,
-			LevelWarn:  _warnValue,
-			LevelError: _errorValue,
-		},
+		names: names,
 	}
 }
 
-func (c *levelsConfig) encode(level Level, encoder zapcore.PrimitiveArrayEncoder) {
-	if value, ok := c.values[level]; ok {
-		encoder.AppendString(value)
-	} else {
-		encoder.AppendString(level.String())
+func (c *levelsConfig) encode(level zapcore.Level, encoder zapcore.PrimitiveArrayEncoder) {
+	encoder.AppendString(formatLevel(Level(level), c.names))
+}
+
+// toZapLevel
+// Преобразует Level в zapcore.Level, ограничивая значение диапазоном int8
+func toZapLevel(level Level) zapcore.Level {
+	return zapcore.Level(min(max(level, math.MinInt8), math.MaxInt8))
+}
+
+// noopHook
+// Хук, отключающий завершающее поведение zap для уровней, совпадающих с zapcore.PanicLevel и zapcore.FatalLevel
+type noopHook struct{}
+
+func (noopHook) OnWrite(*zapcore.CheckedEntry, []zapcore.Field) {}
+
+// reportCore
+// Обертка над zapcore.Core, сообщающая о результате записи каждого лога получателю Options.Stats и
+// Options.ErrorHandler
+type reportCore struct {
+	zapcore.Core
+
+	opt *Options
+}
+
+func (c *reportCore) With(fields []zapcore.Field) zapcore.Core {
+	return &reportCore{
+		Core: c.Core.With(fields),
+		opt:  c.opt,
+	}
+}
+
+func (c *reportCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
 	}
+
+	return ce
+}
+
+func (c *reportCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	err := c.Core.Write(ent, fields)
+	c.opt.report(Level(ent.Level), ent.LoggerName, err)
+
+	return err
 }
 
 type zapWriter struct {
@@ -0,0 +1,151 @@
+//go:build anticrew_log_zap
+
+package log
+
+import (
+	"slices"
+	"time"
+
+	"github.com/anticrew/go-x/xio"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+var _zapBufferPool = buffer.NewPool()
+
+// zapParamsEncoder
+// Реализация zapcore.Encoder для форматов, записываемых общим для драйверов paramsEncoder. Аргументы сохраняются в
+// порядке добавления
+type zapParamsEncoder struct {
+	enc paramsEncoder
+	opt *Options
+
+	// params
+	// Аргументы, добавленные с помощью zapcore.Core.With
+	params []param
+
+	// prefix
+	// Префикс ключей аргументов из пространств имен, открытых с помощью OpenNamespace
+	prefix string
+}
+
+func newZapParamsEncoder(opt Options) *zapParamsEncoder {
+	return &zapParamsEncoder{
+		enc: newParamsEncoder(opt),
+		opt: &opt,
+	}
+}
+
+func (e *zapParamsEncoder) Clone() zapcore.Encoder {
+	return e.clone()
+}
+
+func (e *zapParamsEncoder) clone() *zapParamsEncoder {
+	ne := *e
+	ne.params = slices.Clip(e.params)
+
+	return &ne
+}
+
+func (e *zapParamsEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	ne := e.clone()
+	for _, f := range fields {
+		f.AddTo(ne)
+	}
+
+	params := ne.params
+	if len(ent.LoggerName) > 0 && len(e.opt.NameKey) > 0 {
+		name := param{key: e.opt.NameKey, value: ent.LoggerName}
+
+		// name is written before source, as in slog driver
+		if n := len(params); e.opt.AddSource && n > 0 && params[n-1].key == e.opt.SourceKey {
+			params = slices.Insert(params, n-1, name)
+		} else {
+			params = append(params, name)
+		}
+	}
+
+	buf := xio.NewBuffer()
+	defer buf.Dispose()
+
+	e.enc.encode(buf, Level(ent.Level), ent.Time, ent.Message, params)
+
+	out := _zapBufferPool.Get()
+	out.AppendBytes(buf.Bytes())
+
+	return out, nil
+}
+
+func (e *zapParamsEncoder) add(key string, value any) {
+	e.params = append(e.params, param{
+		key:   e.prefix + key,
+		value: value,
+	})
+}
+
+func (e *zapParamsEncoder) AddArray(key string, marshaler zapcore.ArrayMarshaler) error {
+	m := zapcore.NewMapObjectEncoder()
+	err := m.AddArray(key, marshaler)
+	e.add(key, m.Fields[key])
+
+	return err
+}
+
+func (e *zapParamsEncoder) AddObject(key string, marshaler zapcore.ObjectMarshaler) error {
+	m := zapcore.NewMapObjectEncoder()
+	err := m.AddObject(key, marshaler)
+	e.add(key, m.Fields[key])
+
+	return err
+}
+
+func (e *zapParamsEncoder) AddBinary(key string, value []byte) { e.add(key, value) }
+
+func (e *zapParamsEncoder) AddByteString(key string, value []byte) { e.add(key, string(value)) }
+
+func (e *zapParamsEncoder) AddBool(key string, value bool) { e.add(key, value) }
+
+func (e *zapParamsEncoder) AddComplex128(key string, value complex128) { e.add(key, value) }
+
+func (e *zapParamsEncoder) AddComplex64(key string, value complex64) { e.add(key, value) }
+
+func (e *zapParamsEncoder) AddDuration(key string, value time.Duration) { e.add(key, value) }
+
+func (e *zapParamsEncoder) AddFloat64(key string, value float64) { e.add(key, value) }
+
+func (e *zapParamsEncoder) AddFloat32(key string, value float32) { e.add(key, value) }
+
+func (e *zapParamsEncoder) AddInt(key string, value int) { e.add(key, value) }
+
+func (e *zapParamsEncoder) AddInt64(key string, value int64) { e.add(key, value) }
+
+func (e *zapParamsEncoder) AddInt32(key string, value int32) { e.add(key, value) }
+
+func (e *zapParamsEncoder) AddInt16(key string, value int16) { e.add(key, value) }
+
+func (e *zapParamsEncoder) AddInt8(key string, value int8) { e.add(key, value) }
+
+func (e *zapParamsEncoder) AddString(key, value string) { e.add(key, value) }
+
+func (e *zapParamsEncoder) AddTime(key string, value time.Time) { e.add(key, value) }
+
+func (e *zapParamsEncoder) AddUint(key string, value uint) { e.add(key, value) }
+
+func (e *zapParamsEncoder) AddUint64(key string, value uint64) { e.add(key, value) }
+
+func (e *zapParamsEncoder) AddUint32(key string, value uint32) { e.add(key, value) }
+
+func (e *zapParamsEncoder) AddUint16(key string, value uint16) { e.add(key, value) }
+
+func (e *zapParamsEncoder) AddUint8(key string, value uint8) { e.add(key, value) }
+
+func (e *zapParamsEncoder) AddUintptr(key string, value uintptr) { e.add(key, value) }
+
+func (e *zapParamsEncoder) AddReflected(key string, value any) error {
+	e.add(key, value)
+	return nil
+}
+
+func (e *zapParamsEncoder) OpenNamespace(key string) {
+	e.prefix += key + "."
+}